@@ -2,21 +2,43 @@ package main
 
 import (
 	"fmt"
-	log "github.com/surmus/tire-change-workshop/internal/london"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"github.com/surmus/tire-change-workshop/internal/london"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/net/webdav"
 	"net/http"
 	"os"
+	"regexp"
+	"strings"
 	"time"
 )
 
 const (
-	version        = "v2.0.0"
-	listenPortFlag = "port"
-	verboseFlag    = "verbose"
-	defaultPort    = 9003
+	version         = "v2.0.0"
+	listenPortFlag  = "port"
+	verboseFlag     = "verbose"
+	baysFlag        = "bays-per-time-slot"
+	adminFlag       = "admin-api"
+	maxBookingsFlag = "max-bookings-per-contact"
+	minLeadTimeFlag = "min-booking-lead-time"
+	maxAdvanceFlag  = "max-booking-advance"
+	nameFlag        = "workshop-name"
+	addressFlag     = "workshop-address"
+	latitudeFlag    = "workshop-latitude"
+	longitudeFlag   = "workshop-longitude"
+	openingFlag     = "workshop-opening-hours"
+	branchFlag      = "branch"
+	deprecationFlag = "v1-deprecation"
+	sunsetFlag      = "v1-sunset"
+	dateLayout      = "2006-01-02"
+	defaultPort     = 9003
 )
 
+var branchIDPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
 var flags = []cli.Flag{
 	&cli.StringFlag{
 		Name:    listenPortFlag,
@@ -28,6 +50,64 @@ var flags = []cli.Flag{
 		Name:  verboseFlag,
 		Usage: "Enables debug messages print with SQL logging",
 	},
+	&cli.UintFlag{
+		Name:  baysFlag,
+		Value: 1,
+		Usage: "Amount of bays per tire change time, each initially staffed by one mechanic",
+	},
+	&cli.BoolFlag{
+		Name:  adminFlag,
+		Usage: "Enables unauthenticated /admin routes managing mechanic shifts and absences",
+	},
+	&cli.UintFlag{
+		Name:  maxBookingsFlag,
+		Usage: "Maximum amount of active future bookings per contact, 0 disables the limit",
+	},
+	&cli.DurationFlag{
+		Name:  minLeadTimeFlag,
+		Usage: "Minimum time between booking and tire change time, e.g. 2h",
+	},
+	&cli.DurationFlag{
+		Name:  maxAdvanceFlag,
+		Usage: "Maximum time in advance tire change time can be booked, e.g. 720h, 0 disables the limit",
+	},
+	&cli.StringFlag{
+		Name:  nameFlag,
+		Value: london.DefaultWorkshopInfo.Name,
+		Usage: "Workshop name published by workshop information endpoint",
+	},
+	&cli.StringFlag{
+		Name:  addressFlag,
+		Value: london.DefaultWorkshopInfo.Address,
+		Usage: "Workshop address",
+	},
+	&cli.Float64Flag{
+		Name:  latitudeFlag,
+		Value: london.DefaultWorkshopInfo.Latitude,
+		Usage: "Workshop location latitude",
+	},
+	&cli.Float64Flag{
+		Name:  longitudeFlag,
+		Value: london.DefaultWorkshopInfo.Longitude,
+		Usage: "Workshop location longitude",
+	},
+	&cli.StringFlag{
+		Name:  openingFlag,
+		Value: london.DefaultWorkshopInfo.OpeningHours,
+		Usage: "Workshop opening hours in OpenStreetMap opening_hours syntax",
+	},
+	&cli.StringSliceFlag{
+		Name:  branchFlag,
+		Usage: "Additional branch as id=name, e.g. camden=\"London Camden\", sharing other workshop settings, may be repeated",
+	},
+	&cli.StringFlag{
+		Name:  deprecationFlag,
+		Usage: "Date of v1 API deprecation announced to clients, e.g. 2026-10-19, deprecation is not announced by default",
+	},
+	&cli.StringFlag{
+		Name:  sunsetFlag,
+		Usage: "Date of v1 API withdrawal announced to clients, e.g. 2027-04-30, requires v1 deprecation date",
+	},
 }
 
 // @title London tire workshop API
@@ -63,14 +143,81 @@ func initServer(c *cli.Context) error {
 		log.SetLevel(log.InfoLevel)
 	}
 
-	return setupServer(listenToPort, c.Bool(verboseFlag))
+	var deprecation, sunset time.Time
+	var err error
+
+	if c.IsSet(deprecationFlag) {
+		if deprecation, err = time.Parse(dateLayout, c.String(deprecationFlag)); err != nil {
+			return fmt.Errorf("invalid v1 deprecation date supplied: %s", c.String(deprecationFlag))
+		}
+	}
+
+	if c.IsSet(sunsetFlag) {
+		if sunset, err = time.Parse(dateLayout, c.String(sunsetFlag)); err != nil || sunset.Before(deprecation) ||
+			deprecation.IsZero() {
+			return fmt.Errorf("invalid v1 sunset date supplied: %s", c.String(sunsetFlag))
+		}
+	}
+
+	config := london.Config{
+		BaysPerTimeSlot:             c.Uint(baysFlag),
+		AdminAPI:                    c.Bool(adminFlag),
+		MaxActiveBookingsPerContact: c.Uint(maxBookingsFlag),
+		MinBookingLeadTime:          c.Duration(minLeadTimeFlag),
+		MaxBookingAdvance:           c.Duration(maxAdvanceFlag),
+		Workshop: london.WorkshopInfo{
+			Name:         c.String(nameFlag),
+			Address:      c.String(addressFlag),
+			Latitude:     c.Float64(latitudeFlag),
+			Longitude:    c.Float64(longitudeFlag),
+			OpeningHours: c.String(openingFlag),
+		},
+		V1Deprecation: deprecation,
+		V1Sunset:      sunset,
+	}
+
+	branches, err := parseBranches(c.StringSlice(branchFlag), config)
+
+	if err != nil {
+		return err
+	}
+
+	config.Branches = branches
+
+	return setupServer(listenToPort, c.Bool(verboseFlag), config)
 }
 
-func setupServer(port uint, debugMode bool) error {
-	apiRouter := london.Init(debugMode)
+// parseBranches creates configuration of additional branches given as id=name, other settings are copied from
+// the default branch
+func parseBranches(values []string, defaultBranch london.Config) ([]london.Config, error) {
+	branches := make([]london.Config, 0, len(values))
+	ids := map[string]bool{london.DefaultBranchID: true}
+
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+
+		if len(parts) < 2 || parts[1] == "" || !branchIDPattern.MatchString(parts[0]) || ids[parts[0]] {
+			return nil, fmt.Errorf("invalid branch supplied: %s", value)
+		}
+
+		branch := defaultBranch
+		branch.BranchID = parts[0]
+		branch.Workshop.Name = parts[1]
+		ids[branch.BranchID] = true
+		branches = append(branches, branch)
+	}
+
+	return branches, nil
+}
+
+func setupServer(port uint, debugMode bool, config london.Config) error {
+	apiRouter := london.Init(debugMode, config)
 	// The url pointing to API definition
 	swaggerURL := ginSwagger.URL("swagger/doc.json")
 	apiRouter.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, swaggerURL))
+	// Swagger UI of OpenAPI 3 documents
+	apiRouter.GET("/openapi/*any", openAPIUI("/openapi.json"))
+	apiRouter.GET("/openapi-v2/*any", openAPIUI("/openapi-v2.json"))
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", port),
 		Handler:      apiRouter,
@@ -80,3 +227,11 @@ func setupServer(port uint, debugMode bool) error {
 	log.Infof("application initialized, listening to port %d", port)
 	return server.ListenAndServe()
 }
+
+// openAPIUI serves Swagger UI of OpenAPI document at given url, every UI route has own file handler as the handler
+// is bound to path prefix of the route
+func openAPIUI(url string) gin.HandlerFunc {
+	files := &webdav.Handler{FileSystem: swaggerFiles.FS, LockSystem: webdav.NewMemLS()}
+
+	return ginSwagger.WrapHandler(files, ginSwagger.URL(url))
+}
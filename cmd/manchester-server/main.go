@@ -2,23 +2,41 @@ package main
 
 import (
 	"fmt"
+	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 	"github.com/surmus/tire-change-workshop/internal/manchester"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/net/webdav"
 	"net/http"
 	"os"
+	"regexp"
+	"strings"
 	"time"
 )
 
 const (
-	version        = "v2.0.0"
-	listenPortFlag = "port"
-	verboseFlag    = "verbose"
-	defaultPort    = 9004
+	version         = "v2.0.0"
+	listenPortFlag  = "port"
+	verboseFlag     = "verbose"
+	baysFlag        = "bays-per-time-slot"
+	adminFlag       = "admin-api"
+	maxBookingsFlag = "max-bookings-per-contact"
+	minLeadTimeFlag = "min-booking-lead-time"
+	maxAdvanceFlag  = "max-booking-advance"
+	nameFlag        = "workshop-name"
+	addressFlag     = "workshop-address"
+	latitudeFlag    = "workshop-latitude"
+	longitudeFlag   = "workshop-longitude"
+	openingFlag     = "workshop-opening-hours"
+	branchFlag      = "branch"
+	policyFlag      = "booking-policy"
+	defaultPort     = 9004
 )
 
+var branchIDPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
 var flags = []cli.Flag{
 	&cli.StringFlag{
 		Name:    listenPortFlag,
@@ -30,6 +48,61 @@ var flags = []cli.Flag{
 		Name:  verboseFlag,
 		Usage: "Enables debug messages print with SQL logging",
 	},
+	&cli.UintFlag{
+		Name:  baysFlag,
+		Value: 1,
+		Usage: "Amount of bays per tire change time, each initially staffed by one mechanic",
+	},
+	&cli.BoolFlag{
+		Name:  adminFlag,
+		Usage: "Enables unauthenticated /admin routes managing mechanic shifts and absences",
+	},
+	&cli.UintFlag{
+		Name:  maxBookingsFlag,
+		Usage: "Maximum amount of active future bookings per contact, 0 disables the limit",
+	},
+	&cli.DurationFlag{
+		Name:  minLeadTimeFlag,
+		Usage: "Minimum time between booking and tire change time, e.g. 2h",
+	},
+	&cli.DurationFlag{
+		Name:  maxAdvanceFlag,
+		Usage: "Maximum time in advance tire change time can be booked, e.g. 720h, 0 disables the limit",
+	},
+	&cli.StringFlag{
+		Name:  policyFlag,
+		Value: string(manchester.StrictBookingPolicy),
+		Usage: "Handling of repeated booking by the same contact, \"strict\" rejects and \"idempotent\" accepts it",
+	},
+	&cli.StringFlag{
+		Name:  nameFlag,
+		Value: manchester.DefaultWorkshopInfo.Name,
+		Usage: "Workshop name published by workshop information endpoint",
+	},
+	&cli.StringFlag{
+		Name:  addressFlag,
+		Value: manchester.DefaultWorkshopInfo.Address,
+		Usage: "Workshop address",
+	},
+	&cli.Float64Flag{
+		Name:  latitudeFlag,
+		Value: manchester.DefaultWorkshopInfo.Latitude,
+		Usage: "Workshop location latitude",
+	},
+	&cli.Float64Flag{
+		Name:  longitudeFlag,
+		Value: manchester.DefaultWorkshopInfo.Longitude,
+		Usage: "Workshop location longitude",
+	},
+	&cli.StringFlag{
+		Name:  openingFlag,
+		Value: manchester.DefaultWorkshopInfo.OpeningHours,
+		Usage: "Workshop opening hours in OpenStreetMap opening_hours syntax",
+	},
+	&cli.StringSliceFlag{
+		Name:  branchFlag,
+		Usage: "Additional branch as id=name, e.g. salford=\"Manchester Salford\", sharing other workshop settings, may be repeated",
+	},
 }
 
 // @title Manchester tire workshop API
@@ -64,14 +137,69 @@ func initServer(c *cli.Context) error {
 		log.SetLevel(log.InfoLevel)
 	}
 
-	return setupServer(listenToPort, c.Bool(verboseFlag))
+	bookingPolicy := manchester.BookingPolicy(c.String(policyFlag))
+
+	if bookingPolicy != manchester.StrictBookingPolicy && bookingPolicy != manchester.IdempotentBookingPolicy {
+		return fmt.Errorf("invalid booking policy supplied: %s", bookingPolicy)
+	}
+
+	config := manchester.Config{
+		BaysPerTimeSlot:             c.Uint(baysFlag),
+		AdminAPI:                    c.Bool(adminFlag),
+		MaxActiveBookingsPerContact: c.Uint(maxBookingsFlag),
+		MinBookingLeadTime:          c.Duration(minLeadTimeFlag),
+		MaxBookingAdvance:           c.Duration(maxAdvanceFlag),
+		BookingPolicy:               bookingPolicy,
+		Workshop: manchester.WorkshopInfo{
+			Name:         c.String(nameFlag),
+			Address:      c.String(addressFlag),
+			Latitude:     c.Float64(latitudeFlag),
+			Longitude:    c.Float64(longitudeFlag),
+			OpeningHours: c.String(openingFlag),
+		},
+	}
+
+	branches, err := parseBranches(c.StringSlice(branchFlag), config)
+
+	if err != nil {
+		return err
+	}
+
+	config.Branches = branches
+
+	return setupServer(listenToPort, c.Bool(verboseFlag), config)
 }
 
-func setupServer(port uint, debugMode bool) error {
-	apiRouter := manchester.Init(debugMode)
+// parseBranches creates configuration of additional branches given as id=name, other settings are copied from
+// the default branch
+func parseBranches(values []string, defaultBranch manchester.Config) ([]manchester.Config, error) {
+	branches := make([]manchester.Config, 0, len(values))
+	ids := map[string]bool{manchester.DefaultBranchID: true}
+
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+
+		if len(parts) < 2 || parts[1] == "" || !branchIDPattern.MatchString(parts[0]) || ids[parts[0]] {
+			return nil, fmt.Errorf("invalid branch supplied: %s", value)
+		}
+
+		branch := defaultBranch
+		branch.BranchID = parts[0]
+		branch.Workshop.Name = parts[1]
+		ids[branch.BranchID] = true
+		branches = append(branches, branch)
+	}
+
+	return branches, nil
+}
+
+func setupServer(port uint, debugMode bool, config manchester.Config) error {
+	apiRouter := manchester.Init(debugMode, config)
 	// The url pointing to API definition
 	swaggerURL := ginSwagger.URL("swagger/doc.json")
 	apiRouter.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, swaggerURL))
+	// Swagger UI of OpenAPI 3 documents
+	apiRouter.GET("/openapi/*any", openAPIUI("/openapi.json"))
 	workshopServer := &http.Server{
 		Addr:         fmt.Sprintf(":%d", port),
 		Handler:      apiRouter,
@@ -81,3 +209,11 @@ func setupServer(port uint, debugMode bool) error {
 	log.Infof("application initialized, listening to port %d", port)
 	return workshopServer.ListenAndServe()
 }
+
+// openAPIUI serves Swagger UI of OpenAPI document at given url, every UI route has own file handler as the handler
+// is bound to path prefix of the route
+func openAPIUI(url string) gin.HandlerFunc {
+	files := &webdav.Handler{FileSystem: swaggerFiles.FS, LockSystem: webdav.NewMemLS()}
+
+	return ginSwagger.WrapHandler(files, ginSwagger.URL(url))
+}
@@ -1,30 +1,217 @@
 package london
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
-const v1Path = "/api/v1"
+const (
+	v1Path     = "/api/v1"
+	v2Path     = "/api/v2"
+	branchPath = "/branches/:branchId"
+)
+
+// v2Successors maps v1 routes to v2 routes serving the same resource under another path, other v1 routes are
+// succeeded by v2 routes of the same path
+var v2Successors = map[string]string{
+	"/tire-change-times/available": "/tire-change-times",
+}
 
 type controller struct {
-	service *tireChangeTimesService
+	service   *tireChangeTimesService
+	waitlist  *waitlistService
+	tireHotel *tireHotelService
+	workshop  *workshopService
+	staff     *staffService
+}
+
+func newController(
+	service *tireChangeTimesService,
+	waitlist *waitlistService,
+	tireHotel *tireHotelService,
+	workshop *workshopService,
+	staff *staffService,
+) *controller {
+	return &controller{service: service, waitlist: waitlist, tireHotel: tireHotel, workshop: workshop, staff: staff}
 }
 
-func registerController(router *gin.Engine, service *tireChangeTimesService) {
-	c := &controller{service: service}
+// registerController registers routes of every branch under /branches/{branchId}/api/v1 and
+// /branches/{branchId}/api/v2, default branch routes are aliased by /api/v1 and /api/v2. Admin routes are registered
+// only when enabled
+func registerController(
+	router *gin.Engine,
+	defaultBranch *controller,
+	branches map[string]*controller,
+	adminAPI bool,
+) {
+	defaultV1 := router.Group(v1Path, branchMiddleware(map[string]*controller{"": defaultBranch}))
+	branchV1 := router.Group(branchPath+v1Path, branchMiddleware(branches))
+	defaultV2 := router.Group(v2Path, branchMiddleware(map[string]*controller{"": defaultBranch}))
+	branchV2 := router.Group(branchPath+v2Path, branchMiddleware(branches))
 
-	router.GET(v1Path+"/tire-change-times/available", c.getTireChangeTimes)
-	router.PUT(v1Path+"/tire-change-times/:uuid/booking", c.putTireChangeBooking)
+	registerBranchRoutes(defaultV1)
+	registerBranchRoutes(branchV1)
+	registerBranchV2Routes(defaultV2)
+	registerBranchV2Routes(branchV2)
+
+	if adminAPI {
+		for _, group := range []gin.IRoutes{defaultV1, branchV1, defaultV2, branchV2} {
+			registerAdminRoutes(group)
+		}
+	}
+
+	router.GET(v1Path+"/branches", func(ctx *gin.Context) { getBranches(ctx, branches) })
+	router.GET(v2Path+"/branches", func(ctx *gin.Context) { getBranches(ctx, branches) })
+}
+
+func registerBranchRoutes(router gin.IRoutes) {
+	router.GET("/workshop", handle((*controller).getWorkshop))
+	router.GET("/service-types", handle((*controller).getServiceTypes))
+	router.GET("/tire-change-times/available", handle((*controller).getTireChangeTimes))
+	router.GET("/tire-change-times/next-available", handle((*controller).getNextAvailableTireChangeTimes))
+	router.GET("/tire-change-times/daily-availability", handle((*controller).getDailyAvailability))
+	router.GET("/tire-change-times/:uuid", handle((*controller).getTireChangeTime))
+	router.PUT("/tire-change-times/:uuid/booking", handle((*controller).putTireChangeBooking))
+	router.DELETE("/tire-change-times/:uuid/booking", handle((*controller).deleteTireChangeBooking))
+	router.PUT("/tire-change-times/:uuid/booking/tire-set", handle((*controller).putTireChangeBookingTireSet))
+	router.POST("/waitlist", handle((*controller).postWaitlistEntry))
+	router.GET("/waitlist/:uuid", handle((*controller).getWaitlistEntry))
+	router.POST("/tire-sets", handle((*controller).postTireSet))
+	router.GET("/tire-sets", handle((*controller).getTireSets))
+	router.GET("/tire-sets/:uuid", handle((*controller).getTireSet))
+}
+
+// registerBranchV2Routes registers JSON v2 routes sharing v1 actions, except for tire change times listed by pages
+// and booked by POST
+func registerBranchV2Routes(router gin.IRoutes) {
+	router.GET("/workshop", handle((*controller).getWorkshop))
+	router.GET("/service-types", handle((*controller).getServiceTypes))
+	router.GET("/tire-change-times", handle((*controller).getTireChangeTimesPage))
+	router.GET("/tire-change-times/next-available", handle((*controller).getNextAvailableTireChangeTimes))
+	router.GET("/tire-change-times/daily-availability", handle((*controller).getDailyAvailability))
+	router.GET("/tire-change-times/:uuid", handle((*controller).getTireChangeTime))
+	router.POST("/tire-change-times/:uuid/booking", handle((*controller).putTireChangeBooking))
+	router.DELETE("/tire-change-times/:uuid/booking", handle((*controller).deleteTireChangeBooking))
+	router.PUT("/tire-change-times/:uuid/booking/tire-set", handle((*controller).putTireChangeBookingTireSet))
+	router.POST("/waitlist", handle((*controller).postWaitlistEntry))
+	router.GET("/waitlist/:uuid", handle((*controller).getWaitlistEntry))
+	router.POST("/tire-sets", handle((*controller).postTireSet))
+	router.GET("/tire-sets", handle((*controller).getTireSets))
+	router.GET("/tire-sets/:uuid", handle((*controller).getTireSet))
+}
+
+// registerAdminRoutes registers management of mechanic shifts and absences, shared by v1 and v2
+func registerAdminRoutes(router gin.IRoutes) {
+	router.GET("/admin/mechanics", handle((*controller).getMechanics))
+	router.POST("/admin/mechanics", handle((*controller).postMechanic))
+	router.PUT("/admin/mechanics/:uuid/shifts", handle((*controller).putMechanicShifts))
+	router.POST("/admin/mechanics/:uuid/absences", handle((*controller).postMechanicAbsence))
+	router.DELETE("/admin/mechanics/:uuid/absences/:absenceUuid", handle((*controller).deleteMechanicAbsence))
+	router.GET("/admin/rescheduled-bookings", handle((*controller).getRescheduledBookings))
+}
+
+// isV2 tells whether request matched v2 route, v2 routes read and write JSON only
+func isV2(ctx *gin.Context) bool {
+	return strings.Contains(ctx.FullPath(), v2Path)
+}
+
+// handle dispatches request to the controller of branch resolved by branchMiddleware
+func handle(action func(*controller, *gin.Context)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		action(ctx.MustGet(branchContextKey).(*controller), ctx)
+	}
+}
+
+// render writes response in the format negotiated by Accept header, XML is used when header is missing.
+// Responses of v2 routes are always JSON
+func render(ctx *gin.Context, code int, obj interface{}) {
+	if isV2(ctx) {
+		ctx.JSON(code, obj)
+		return
+	}
+
+	switch ctx.NegotiateFormat(gin.MIMEXML, gin.MIMEXML2, gin.MIMEJSON) {
+	case gin.MIMEJSON:
+		ctx.JSON(code, obj)
+	default:
+		ctx.XML(code, obj)
+	}
+}
+
+// bind decodes request body by its Content-Type, XML is assumed when header is missing.
+// Request bodies of v2 routes are always JSON
+func bind(ctx *gin.Context, obj interface{}) error {
+	if isV2(ctx) || ctx.ContentType() == gin.MIMEJSON {
+		return ctx.ShouldBindJSON(obj)
+	}
+
+	return ctx.ShouldBindXML(obj)
+}
+
+// getBranches godoc
+// @Summary List of workshop branches hosted by the server
+// @Description Every branch serves the same API under /branches/{branchId}/api/v1 with its own schedule and bookings,
+// @Description /api/v1 routes serve the default branch.
+// @Accept xml,json
+// @Produce xml,json
+// @Success 200 {object} branchesResponse
+// @Failure 500 {object} errorResponse
+// @Router /branches [get]
+func getBranches(ctx *gin.Context, branches map[string]*controller) {
+	workshops := make(map[string]*workshopResponse, len(branches))
+
+	for id, branch := range branches {
+		workshops[id] = branch.workshop.get()
+	}
+
+	render(ctx, http.StatusOK, newBranchesResponse(workshops))
+}
+
+// getWorkshop godoc
+// @Summary Workshop details with location, opening hours, time zone and serviced vehicle types
+// @Accept xml,json
+// @Produce xml,json
+// @Success 200 {object} workshopResponse
+// @Failure 500 {object} errorResponse
+// @Router /workshop [get]
+func (c *controller) getWorkshop(ctx *gin.Context) {
+	render(ctx, http.StatusOK, c.workshop.get())
+}
+
+// getServiceTypes godoc
+// @Summary List of services provided by the workshop
+// @Accept xml,json
+// @Produce xml,json
+// @Success 200 {object} serviceTypesResponse
+// @Failure 500 {object} errorResponse
+// @Router /service-types [get]
+func (c *controller) getServiceTypes(ctx *gin.Context) {
+	render(ctx, http.StatusOK, c.service.getServiceTypes())
 }
 
 // getTireChangeTimes godoc
 // @Summary List of available tire change times
-// @Accept xml
-// @Produce xml
+// @Description Every tire change time is quoted with the price of given service for given vehicle type.
+// @Description Tire change times of bays restricted to certain vehicle types list them, others service all types.
+// @Accept xml,json
+// @Produce xml,json
 // @Param from query string true "search available times from date" Format(date) default(2006-01-02)
 // @Param until query string true "search available times until date" Format(date) default(2030-01-02)
+// @Param timeFrom query string false "list only tire change times starting at or after the time of day in server time zone" default(08:00)
+// @Param timeUntil query string false "list only tire change times starting before the time of day in server time zone" default(12:00)
+// @Param weekdays query []string false "list only tire change times of the weekdays" collectionFormat(multi) Enums(MONDAY, TUESDAY, WEDNESDAY, THURSDAY, FRIDAY, SATURDAY, SUNDAY)
+// @Param includePast query boolean false "include already passed tire change times" default(false)
+// @Param serviceType query string false "list only start times fitting the service, see service types list"
+// @Param vehicleType query string false "list only tire change times able to service the vehicle type and quote prices for it, defaults to CAR pricing" Enums(CAR, SUV, VAN)
+// @Param limit query integer false "maximum amount of tire change times to return, total amount is added to the response when given"
+// @Param offset query integer false "amount of tire change times to skip, requires limit"
 // @Success 200 {object} tireChangeTimesResponse
+// @Header 200 {string} ETag "Entity tag of the response, send it by If-None-Match header to get 304 when unchanged"
+// @Header 200 {string} Last-Modified "Latest change of tire change times, send it by If-Modified-Since header to get 304 when unchanged"
 // @Failure 400 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /tire-change-times/available [get]
@@ -35,24 +222,168 @@ func (c *controller) getTireChangeTimes(ctx *gin.Context) {
 		panic(validationError{err})
 	}
 
-	availableTimes, err := c.service.getAvailable(query.From, query.Until)
+	availableTimes, err := c.service.getAvailable(&query)
+
+	if err != nil {
+		panic(err)
+	}
+
+	ctx.Header("Last-Modified", c.service.lastModified().UTC().Format(http.TimeFormat))
+	render(ctx, http.StatusOK, availableTimes)
+}
+
+// getTireChangeTimesPage lists page of available tire change times of v2 API described by its OpenAPI document,
+// neighbouring pages are linked by RFC 8288 Link header
+func (c *controller) getTireChangeTimesPage(ctx *gin.Context) {
+	var query tireChangeTimesPageQuery
+
+	if err := ctx.ShouldBind(&query); err != nil {
+		panic(validationError{err})
+	}
+
+	page, err := c.service.getPage(&query)
+
+	if err != nil {
+		panic(err)
+	}
+
+	var links []string
+
+	if query.Offset > 0 {
+		previousOffset := uint(0)
+
+		if query.Offset > query.Limit {
+			previousOffset = query.Offset - query.Limit
+		}
+
+		links = append(links, pageLink(ctx, previousOffset, "prev"))
+	}
+
+	if page.hasNext() {
+		links = append(links, pageLink(ctx, query.Offset+query.Limit, "next"))
+	}
+
+	if len(links) > 0 {
+		ctx.Header("Link", strings.Join(links, ", "))
+	}
+
+	ctx.Header("Last-Modified", c.service.lastModified().UTC().Format(http.TimeFormat))
+	render(ctx, http.StatusOK, page)
+}
+
+func pageLink(ctx *gin.Context, offset uint, rel string) string {
+	values := ctx.Request.URL.Query()
+	values.Set("offset", strconv.FormatUint(uint64(offset), 10))
+
+	link := url.URL{Path: ctx.Request.URL.Path, RawQuery: values.Encode()}
+
+	return fmt.Sprintf(`<%s>; rel="%s"`, link.String(), rel)
+}
+
+// getDailyAvailability godoc
+// @Summary Daily counts of tire change times
+// @Description Counts all, available and booked tire change times of every day having them in server time zone.
+// @Description Upcoming tire change time having free places left is counted as available, tire change time having
+// @Description all places booked as booked. Passed and closed tire change times are counted only in total.
+// @Accept xml,json
+// @Produce xml,json
+// @Param from query string true "summarize tire change times from date" Format(date) default(2006-01-02)
+// @Param until query string true "summarize tire change times until date inclusive" Format(date) default(2030-01-02)
+// @Param vehicleType query string false "count only tire change times able to service the vehicle type" Enums(CAR, SUV, VAN)
+// @Success 200 {object} dailyAvailabilityResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /tire-change-times/daily-availability [get]
+func (c *controller) getDailyAvailability(ctx *gin.Context) {
+	var query dailyAvailabilityQuery
+
+	if err := ctx.ShouldBind(&query); err != nil {
+		panic(validationError{err})
+	}
+
+	response, err := c.service.getDailyAvailability(&query)
 
 	if err != nil {
 		panic(err)
 	}
 
-	ctx.XML(http.StatusOK, availableTimes)
+	render(ctx, http.StatusOK, response)
+}
+
+// getNextAvailableTireChangeTimes godoc
+// @Summary Earliest available tire change times after given time
+// @Description Tire change times are quoted with the price of the default service for given vehicle type.
+// @Accept xml,json
+// @Produce xml,json
+// @Param after query string false "list tire change times starting after the time, defaults to now" Format(date-time) default(2006-01-02T15:04:05Z)
+// @Param count query integer false "amount of tire change times to return" minimum(1) maximum(50) default(1)
+// @Param vehicleType query string false "list only tire change times able to service the vehicle type and quote prices for it, defaults to CAR pricing" Enums(CAR, SUV, VAN)
+// @Success 200 {object} tireChangeTimesResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /tire-change-times/next-available [get]
+func (c *controller) getNextAvailableTireChangeTimes(ctx *gin.Context) {
+	var query nextAvailableQuery
+
+	if err := ctx.ShouldBind(&query); err != nil {
+		panic(validationError{err})
+	}
+
+	response, err := c.service.getNextAvailable(&query)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, response)
+}
+
+// getTireChangeTime godoc
+// @Summary Single tire change time with its remaining capacity
+// @Description Tire change time is quoted with the price of given service for given vehicle type.
+// @Accept xml,json
+// @Produce xml,json
+// @Param uuid path string true "tire change time UUID" minlength(36) maxlength(36)
+// @Param serviceType query string false "quote price of the service, see service types list"
+// @Param vehicleType query string false "quote price for the vehicle type, defaults to CAR pricing" Enums(CAR, SUV, VAN)
+// @Success 200 {object} tireChangeBookingResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /tire-change-times/{uuid} [get]
+func (c *controller) getTireChangeTime(ctx *gin.Context) {
+	var uri tireChangeTimeURI
+	var query tireChangeTimeQuery
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(validationError{err})
+	} else if err := ctx.ShouldBind(&query); err != nil {
+		panic(validationError{err})
+	}
+
+	tireChangeTime, err := c.service.getByUUID(uri.UUID, &query)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, tireChangeTime)
 }
 
 // putTireChangeBooking godoc
 // @Summary Book tire change time
-// @Accept xml
-// @Produce xml
+// @Description Services lasting longer than a single tire change time reserve consecutive tire change times,
+// @Description tire change service is booked when service type is not given.
+// @Description Quoted price is agreed with the booking and returned in the response.
+// @Description Tire set of the contact stored in tire hotel can be attached to be fetched before the appointment,
+// @Description repeated booking keeps the tire set of the booking, use booking/tire-set endpoint to change it.
+// @Accept xml,json
+// @Produce xml,json
 // @Param uuid path string true "available tire change time UUID" minlength(36) maxlength(36)
 // @Param body body tireChangeBookingRequest true "Request body"
 // @Success 200 {object} tireChangeBookingResponse
 // @Failure 400 {object} errorResponse
-// @Failure 422 {object} errorResponse "The tire change time has already been booked by another contact"
+// @Failure 422 {object} errorResponse "The tire change time has already been booked by another contact, is not followed by enough available times for the service, overlaps another booking of the contact, is outside of booking window, contact has reached active bookings limit, tire set is not stored for the contact or vehicle type is not supported by the bay"
 // @Failure 500 {object} errorResponse
 // @Router /tire-change-times/{uuid}/booking [put]
 func (c *controller) putTireChangeBooking(ctx *gin.Context) {
@@ -61,15 +392,325 @@ func (c *controller) putTireChangeBooking(ctx *gin.Context) {
 
 	if err := ctx.ShouldBindUri(&uri); err != nil {
 		panic(validationError{err})
-	} else if err := ctx.ShouldBindXML(&request); err != nil {
+	} else if err := bind(ctx, &request); err != nil {
+		panic(validationError{err})
+	}
+
+	booking, err := c.service.book(uri.UUID, &request)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, booking)
+}
+
+// putTireChangeBookingTireSet godoc
+// @Summary Attach tire set stored in tire hotel to booked tire change time
+// @Description Tire set is attached to all tire change times reserved together by the booking.
+// @Accept xml,json
+// @Produce xml,json
+// @Param uuid path string true "booked tire change time UUID" minlength(36) maxlength(36)
+// @Param body body tireChangeBookingTireSetRequest true "Request body"
+// @Success 200 {object} tireChangeBookingResponse
+// @Failure 400 {object} errorResponse
+// @Failure 422 {object} errorResponse "The tire change time is not booked by given contact or tire set is not stored for the contact"
+// @Failure 500 {object} errorResponse
+// @Router /tire-change-times/{uuid}/booking/tire-set [put]
+func (c *controller) putTireChangeBookingTireSet(ctx *gin.Context) {
+	var uri tireChangeBookingURI
+	var request tireChangeBookingTireSetRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(validationError{err})
+	} else if err := bind(ctx, &request); err != nil {
+		panic(validationError{err})
+	}
+
+	booking, err := c.service.attachTireSet(uri.UUID, &request)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, booking)
+}
+
+// deleteTireChangeBooking godoc
+// @Summary Cancel tire change time booking, released times are assigned to the first matching waitlist entries
+// @Accept xml,json
+// @Produce xml,json
+// @Param uuid path string true "booked tire change time UUID" minlength(36) maxlength(36)
+// @Param body body tireChangeBookingCancellationRequest true "Request body"
+// @Success 200 {object} tireChangeBookingResponse
+// @Failure 400 {object} errorResponse
+// @Failure 422 {object} errorResponse "The tire change time is not booked by given contact"
+// @Failure 500 {object} errorResponse
+// @Router /tire-change-times/{uuid}/booking [delete]
+func (c *controller) deleteTireChangeBooking(ctx *gin.Context) {
+	var uri tireChangeBookingURI
+	var request tireChangeBookingCancellationRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(validationError{err})
+	} else if err := bind(ctx, &request); err != nil {
+		panic(validationError{err})
+	}
+
+	booking, err := c.service.cancelBooking(uri.UUID, request.ContactInformation)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, booking)
+}
+
+// postWaitlistEntry godoc
+// @Summary Register interest in fully booked tire change time or day
+// @Description Either tireChangeTimeUuid or date must be given. When matching time is free it is booked right away,
+// @Description otherwise it is assigned to the contact once it gets released.
+// @Accept xml,json
+// @Produce xml,json
+// @Param body body waitlistRequest true "Request body"
+// @Success 200 {object} waitlistEntryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 422 {object} errorResponse "The tire change time does not exist"
+// @Failure 500 {object} errorResponse
+// @Router /waitlist [post]
+func (c *controller) postWaitlistEntry(ctx *gin.Context) {
+	var request waitlistRequest
+
+	if err := bind(ctx, &request); err != nil {
+		panic(validationError{err})
+	}
+
+	entry, err := c.waitlist.register(&request)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, entry)
+}
+
+// getWaitlistEntry godoc
+// @Summary Waitlist entry status with assigned tire change time
+// @Accept xml,json
+// @Produce xml,json
+// @Param uuid path string true "waitlist entry UUID" minlength(36) maxlength(36)
+// @Success 200 {object} waitlistEntryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /waitlist/{uuid} [get]
+func (c *controller) getWaitlistEntry(ctx *gin.Context) {
+	var uri waitlistEntryURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(validationError{err})
+	}
+
+	entry, err := c.waitlist.get(uri.UUID)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, entry)
+}
+
+// postTireSet godoc
+// @Summary Register tire set stored in tire hotel for the contact
+// @Accept xml,json
+// @Produce xml,json
+// @Param body body tireSetRequest true "Request body"
+// @Success 200 {object} tireSetResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /tire-sets [post]
+func (c *controller) postTireSet(ctx *gin.Context) {
+	var request tireSetRequest
+
+	if err := bind(ctx, &request); err != nil {
+		panic(validationError{err})
+	}
+
+	render(ctx, http.StatusOK, c.tireHotel.register(&request))
+}
+
+// getTireSets godoc
+// @Summary List of tire sets stored in tire hotel for the contact
+// @Accept xml,json
+// @Produce xml,json
+// @Param contactInformation query string true "contact owning tire sets"
+// @Success 200 {object} tireSetsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /tire-sets [get]
+func (c *controller) getTireSets(ctx *gin.Context) {
+	var query tireSetsSearchQuery
+
+	if err := ctx.ShouldBind(&query); err != nil {
+		panic(validationError{err})
+	}
+
+	render(ctx, http.StatusOK, c.tireHotel.getByContact(&query))
+}
+
+// getTireSet godoc
+// @Summary Tire set stored in tire hotel
+// @Accept xml,json
+// @Produce xml,json
+// @Param uuid path string true "tire set UUID" minlength(36) maxlength(36)
+// @Success 200 {object} tireSetResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /tire-sets/{uuid} [get]
+func (c *controller) getTireSet(ctx *gin.Context) {
+	var uri tireSetURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(validationError{err})
+	}
+
+	tireSet, err := c.tireHotel.get(uri.UUID)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, tireSet)
+}
+
+// getMechanics godoc
+// @Summary List of workshop mechanics with their weekly shifts and absences
+// @Accept xml,json
+// @Produce xml,json
+// @Success 200 {object} mechanicsResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/mechanics [get]
+func (c *controller) getMechanics(ctx *gin.Context) {
+	render(ctx, http.StatusOK, c.staff.getAll())
+}
+
+// postMechanic godoc
+// @Summary Register workshop mechanic, mechanic is not on duty until shifts are set
+// @Accept xml,json
+// @Produce xml,json
+// @Param body body mechanicRequest true "Request body"
+// @Success 200 {object} mechanicResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/mechanics [post]
+func (c *controller) postMechanic(ctx *gin.Context) {
+	var request mechanicRequest
+
+	if err := bind(ctx, &request); err != nil {
+		panic(validationError{err})
+	}
+
+	render(ctx, http.StatusOK, c.staff.register(&request))
+}
+
+// putMechanicShifts godoc
+// @Summary Replace weekly shifts of the mechanic
+// @Description Every mechanic on duty operates single bay, so the amount of bookable places of all future tire change
+// @Description times is recalculated. Bookings left without mechanic are flagged for rescheduling.
+// @Accept xml,json
+// @Produce xml,json
+// @Param uuid path string true "mechanic UUID" minlength(36) maxlength(36)
+// @Param body body mechanicShiftsRequest true "Request body"
+// @Success 200 {object} mechanicResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/mechanics/{uuid}/shifts [put]
+func (c *controller) putMechanicShifts(ctx *gin.Context) {
+	var uri mechanicURI
+	var request mechanicShiftsRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(validationError{err})
+	} else if err := bind(ctx, &request); err != nil {
 		panic(validationError{err})
 	}
 
-	booking, err := c.service.book(uri.UUID, request.ContactInformation)
+	mechanic, err := c.staff.replaceShifts(uri.UUID, &request)
 
 	if err != nil {
 		panic(err)
 	}
 
-	ctx.XML(http.StatusOK, booking)
+	render(ctx, http.StatusOK, mechanic)
+}
+
+// postMechanicAbsence godoc
+// @Summary Register day off or sick leave of the mechanic
+// @Description Bookings of the day left without mechanic are flagged for rescheduling and returned in the response.
+// @Accept xml,json
+// @Produce xml,json
+// @Param uuid path string true "mechanic UUID" minlength(36) maxlength(36)
+// @Param body body mechanicAbsenceRequest true "Request body"
+// @Success 200 {object} mechanicAbsenceResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/mechanics/{uuid}/absences [post]
+func (c *controller) postMechanicAbsence(ctx *gin.Context) {
+	var uri mechanicURI
+	var request mechanicAbsenceRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(validationError{err})
+	} else if err := bind(ctx, &request); err != nil {
+		panic(validationError{err})
+	}
+
+	absence, err := c.staff.registerAbsence(uri.UUID, &request)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, absence)
+}
+
+// deleteMechanicAbsence godoc
+// @Summary Cancel absence of the mechanic, released places are assigned to the first matching waitlist entries
+// @Accept xml,json
+// @Produce xml,json
+// @Param uuid path string true "mechanic UUID" minlength(36) maxlength(36)
+// @Param absenceUuid path string true "absence UUID" minlength(36) maxlength(36)
+// @Success 200 {object} mechanicResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/mechanics/{uuid}/absences/{absenceUuid} [delete]
+func (c *controller) deleteMechanicAbsence(ctx *gin.Context) {
+	var uri mechanicAbsenceURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(validationError{err})
+	}
+
+	mechanic, err := c.staff.removeAbsence(uri.UUID, uri.AbsenceUUID)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, mechanic)
+}
+
+// getRescheduledBookings godoc
+// @Summary List of upcoming bookings left without mechanic, contacts have to be offered another time
+// @Accept xml,json
+// @Produce xml,json
+// @Success 200 {object} rescheduledBookingsResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/rescheduled-bookings [get]
+func (c *controller) getRescheduledBookings(ctx *gin.Context) {
+	render(ctx, http.StatusOK, c.staff.getRescheduledBookings())
 }
@@ -2,6 +2,8 @@ package london
 
 import (
 	"fmt"
+	"github.com/surmus/tire-change-workshop/internal/shared"
+	"net/http"
 	"time"
 )
 
@@ -9,8 +11,13 @@ type validationError struct {
 	error
 }
 
+func (e validationError) Unwrap() error {
+	return e.error
+}
+
 type unAvailableBookingError struct {
-	error string
+	error        string
+	alternatives []*tireChangeBookingResponse
 }
 
 func newUnAvailableBookingError(e *tireChangeTimeEntity) unAvailableBookingError {
@@ -21,6 +28,45 @@ func (e unAvailableBookingError) Error() string {
 	return e.error
 }
 
+// withAlternatives returns the error suggesting given tire change times to book instead
+func (e unAvailableBookingError) withAlternatives(alternatives []*tireChangeBookingResponse) unAvailableBookingError {
+	e.alternatives = alternatives
+
+	return e
+}
+
+type serviceDoesNotFitError struct {
+	error string
+}
+
+func newServiceDoesNotFitError(e *tireChangeTimeEntity, serviceType *serviceTypeEntity) serviceDoesNotFitError {
+	return serviceDoesNotFitError{
+		error: fmt.Sprintf(
+			"tire change time %s is not followed by enough available tire change times for %s",
+			e.UUID,
+			serviceType.Name,
+		),
+	}
+}
+
+func (e serviceDoesNotFitError) Error() string {
+	return e.error
+}
+
+type overlappingBookingError struct {
+	error string
+}
+
+func newOverlappingBookingError(e *tireChangeTimeEntity) overlappingBookingError {
+	return overlappingBookingError{
+		error: fmt.Sprintf("tire change time %s is already booked by the contact for another service", e.UUID),
+	}
+}
+
+func (e overlappingBookingError) Error() string {
+	return e.error
+}
+
 type invalidTireChangeTimesPeriodError struct {
 	error string
 }
@@ -34,3 +80,259 @@ func newInvalidTirChangeTimesPeriodError(from time.Time, until time.Time) invali
 func (e invalidTireChangeTimesPeriodError) Error() string {
 	return e.error
 }
+
+type invalidBookingCancellationError struct {
+	error string
+}
+
+func newInvalidBookingCancellationError(e *tireChangeTimeEntity) invalidBookingCancellationError {
+	return invalidBookingCancellationError{
+		error: fmt.Sprintf("tire change time %s is not booked by given contact", e.UUID),
+	}
+}
+
+func (e invalidBookingCancellationError) Error() string {
+	return e.error
+}
+
+type notBookedByContactError struct {
+	error string
+}
+
+func newNotBookedByContactError(e *tireChangeTimeEntity) notBookedByContactError {
+	return notBookedByContactError{error: fmt.Sprintf("tire change time %s is not booked by given contact", e.UUID)}
+}
+
+func (e notBookedByContactError) Error() string {
+	return e.error
+}
+
+type unknownWaitlistEntryError struct {
+	error string
+}
+
+func newUnknownWaitlistEntryError(uuid string) unknownWaitlistEntryError {
+	return unknownWaitlistEntryError{error: fmt.Sprintf("waitlist entry %s does not exist", uuid)}
+}
+
+func (e unknownWaitlistEntryError) Error() string {
+	return e.error
+}
+
+type bookingLimitExceededError struct {
+	error string
+}
+
+func newBookingLimitExceededError(contactInformation string, limit uint) bookingLimitExceededError {
+	return bookingLimitExceededError{
+		error: fmt.Sprintf("contact %s has reached the limit of %d active bookings", contactInformation, limit),
+	}
+}
+
+func (e bookingLimitExceededError) Error() string {
+	return e.error
+}
+
+type bookingWindowError struct {
+	error string
+}
+
+func newPastBookingError(e *tireChangeTimeEntity) bookingWindowError {
+	return bookingWindowError{error: fmt.Sprintf("tire change time %s has already passed", e.UUID)}
+}
+
+func newBookingLeadTimeError(e *tireChangeTimeEntity, minLeadTime time.Duration) bookingWindowError {
+	return bookingWindowError{
+		error: fmt.Sprintf("tire change time %s must be booked at least %s in advance", e.UUID, minLeadTime),
+	}
+}
+
+func newBookingAdvanceWindowError(e *tireChangeTimeEntity, maxAdvance time.Duration) bookingWindowError {
+	return bookingWindowError{
+		error: fmt.Sprintf("tire change time %s cannot be booked more than %s in advance", e.UUID, maxAdvance),
+	}
+}
+
+func (e bookingWindowError) Error() string {
+	return e.error
+}
+
+type unknownServiceTypeError struct {
+	error string
+}
+
+func newUnknownServiceTypeError(code string) unknownServiceTypeError {
+	return unknownServiceTypeError{error: fmt.Sprintf("service type %s does not exist", code)}
+}
+
+func (e unknownServiceTypeError) Error() string {
+	return e.error
+}
+
+type unknownVehicleTypeError struct {
+	error string
+}
+
+func newUnknownVehicleTypeError(vehicleType string) unknownVehicleTypeError {
+	return unknownVehicleTypeError{error: fmt.Sprintf("vehicle type %s is not serviced", vehicleType)}
+}
+
+func (e unknownVehicleTypeError) Error() string {
+	return e.error
+}
+
+type unknownTireSetError struct {
+	error string
+}
+
+func newUnknownTireSetError(uuid string) unknownTireSetError {
+	return unknownTireSetError{error: fmt.Sprintf("tire set %s does not exist", uuid)}
+}
+
+func (e unknownTireSetError) Error() string {
+	return e.error
+}
+
+type invalidTireSetError struct {
+	error string
+}
+
+func newInvalidTireSetError(uuid string) invalidTireSetError {
+	return invalidTireSetError{error: fmt.Sprintf("tire set %s is not stored for given contact", uuid)}
+}
+
+func (e invalidTireSetError) Error() string {
+	return e.error
+}
+
+type unsupportedVehicleTypeError struct {
+	error string
+}
+
+func newUnsupportedVehicleTypeError(e *tireChangeTimeEntity, vehicleType string) unsupportedVehicleTypeError {
+	return unsupportedVehicleTypeError{
+		error: fmt.Sprintf("tire change time %s bay is not able to service vehicle type %s", e.UUID, vehicleType),
+	}
+}
+
+func (e unsupportedVehicleTypeError) Error() string {
+	return e.error
+}
+
+type unknownMechanicError struct {
+	error string
+}
+
+func newUnknownMechanicError(uuid string) unknownMechanicError {
+	return unknownMechanicError{error: fmt.Sprintf("mechanic %s does not exist", uuid)}
+}
+
+func (e unknownMechanicError) Error() string {
+	return e.error
+}
+
+type unknownMechanicAbsenceError struct {
+	error string
+}
+
+func newUnknownMechanicAbsenceError(mechanic *mechanicEntity, uuid string) unknownMechanicAbsenceError {
+	return unknownMechanicAbsenceError{
+		error: fmt.Sprintf("absence %s of mechanic %s does not exist", uuid, mechanic.UUID),
+	}
+}
+
+func (e unknownMechanicAbsenceError) Error() string {
+	return e.error
+}
+
+type unknownTireChangeTimeError struct {
+	error string
+}
+
+func newUnknownTireChangeTimeError(uuid string) unknownTireChangeTimeError {
+	return unknownTireChangeTimeError{error: fmt.Sprintf("tire change time %s does not exist", uuid)}
+}
+
+func (e unknownTireChangeTimeError) Error() string {
+	return e.error
+}
+
+type unknownBranchError struct {
+	error string
+}
+
+func newUnknownBranchError(id string) unknownBranchError {
+	return unknownBranchError{error: fmt.Sprintf("branch %s does not exist", id)}
+}
+
+func (e unknownBranchError) Error() string {
+	return e.error
+}
+
+// problemTypeOf resolves RFC 7807 problem type URI and title of the error, unexpected errors are of blank type
+// titled by the HTTP status
+func problemTypeOf(err error) (problemType string, title string) {
+	switch err.(type) {
+
+	case validationError:
+		return shared.ProblemTypeURNPrefix + "invalid-request", "Request is invalid"
+
+	case invalidTireChangeTimesPeriodError:
+		return shared.ProblemTypeURNPrefix + "invalid-period", "Tire change times period is invalid"
+
+	case unknownServiceTypeError:
+		return shared.ProblemTypeURNPrefix + "unknown-service-type", "Service type is unknown"
+
+	case unknownVehicleTypeError:
+		return shared.ProblemTypeURNPrefix + "unknown-vehicle-type", "Vehicle type is unknown"
+
+	case unAvailableBookingError:
+		return shared.ProblemTypeURNPrefix + "unavailable-tire-change-time", "Tire change time is unavailable"
+
+	case serviceDoesNotFitError:
+		return shared.ProblemTypeURNPrefix + "service-does-not-fit", "Service does not fit available tire change times"
+
+	case overlappingBookingError:
+		return shared.ProblemTypeURNPrefix + "overlapping-booking",
+			"Tire change times are partly booked by the contact already"
+
+	case bookingWindowError:
+		return shared.ProblemTypeURNPrefix + "outside-booking-window", "Tire change time is outside of booking window"
+
+	case bookingLimitExceededError:
+		return shared.ProblemTypeURNPrefix + "booking-limit-exceeded", "Contact has reached the booking limit"
+
+	case invalidBookingCancellationError:
+		return shared.ProblemTypeURNPrefix + "invalid-cancellation", "Booking can not be cancelled by the contact"
+
+	case notBookedByContactError:
+		return shared.ProblemTypeURNPrefix + "not-booked-by-contact", "Tire change time is not booked by the contact"
+
+	case invalidTireSetError:
+		return shared.ProblemTypeURNPrefix + "invalid-tire-set", "Tire set can not be used for the booking"
+
+	case unsupportedVehicleTypeError:
+		return shared.ProblemTypeURNPrefix + "unsupported-vehicle-type", "Vehicle type is not supported by the bay"
+
+	case unknownWaitlistEntryError:
+		return shared.ProblemTypeURNPrefix + "unknown-waitlist-entry", "Waitlist entry does not exist"
+
+	case unknownTireChangeTimeError:
+		return shared.ProblemTypeURNPrefix + "unknown-tire-change-time", "Tire change time does not exist"
+
+	case unknownTireSetError:
+		return shared.ProblemTypeURNPrefix + "unknown-tire-set", "Tire set does not exist"
+
+	case unknownMechanicError:
+		return shared.ProblemTypeURNPrefix + "unknown-mechanic", "Mechanic does not exist"
+
+	case unknownMechanicAbsenceError:
+		return shared.ProblemTypeURNPrefix + "unknown-mechanic-absence", "Mechanic absence does not exist"
+
+	case unknownBranchError:
+		return shared.ProblemTypeURNPrefix + "unknown-branch", "Branch does not exist"
+
+	default:
+		return "about:blank", http.StatusText(http.StatusInternalServerError)
+	}
+}
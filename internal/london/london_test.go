@@ -2,13 +2,19 @@ package london
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/surmus/tire-change-workshop/internal/shared"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -16,7 +22,7 @@ import (
 const rfc3339DateFormat = "2006-01-02"
 
 func TestGetAvailableTireChangeTimes(t *testing.T) {
-	router := Init(true)
+	router := Init(true, Config{})
 
 	t.Run("successfully get all available for today and tomorrow in correct order", func(t *testing.T) {
 		today := time.Now().Format(rfc3339DateFormat)
@@ -34,6 +40,75 @@ func TestGetAvailableTireChangeTimes(t *testing.T) {
 		verifyTireChangeTimesResponse(t, result)
 	})
 
+	t.Run("successfully hide passed tire change times unless requested", func(t *testing.T) {
+		lastWeek := time.Now().AddDate(0, 0, -7).Format(rfc3339DateFormat)
+		today := time.Now().Format(rfc3339DateFormat)
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/available?from=%s&until=%s", lastWeek, today)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		upcoming := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), upcoming)
+
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, reqURL+"&includePast=true", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		all := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), all)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Greater(t, len(all.AvailableTimes), len(upcoming.AvailableTimes))
+		verifyTireChangeTimesResponse(t, all)
+
+		for _, availableTime := range upcoming.AvailableTimes {
+			assert.True(t, availableTime.Time.After(time.Now()), "passed tire change times should be hidden")
+		}
+	})
+
+	t.Run("successfully get page of available times with total amount", func(t *testing.T) {
+		for _, serviceType := range []string{defaultServiceTypeCode, "WHEEL_BALANCING"} {
+			reqURL := fmt.Sprintf(
+				v1Path+"/tire-change-times/available?from=%s&until=%s&serviceType=%s",
+				time.Now().Format(rfc3339DateFormat),
+				time.Now().AddDate(0, 0, 14).Format(rfc3339DateFormat),
+				serviceType,
+			)
+
+			requestWriter := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+			router.ServeHTTP(requestWriter, req)
+
+			all := &tireChangeTimesResponse{}
+			unMarshal(t, requestWriter.Body.Bytes(), all)
+			assert.Nil(t, all.Total)
+
+			requestWriter = httptest.NewRecorder()
+			req, _ = http.NewRequest(http.MethodGet, reqURL+"&limit=5&offset=3", nil)
+			router.ServeHTTP(requestWriter, req)
+
+			page := &tireChangeTimesResponse{}
+			unMarshal(t, requestWriter.Body.Bytes(), page)
+
+			assert.Equal(t, http.StatusOK, requestWriter.Code)
+			assert.Equal(t, all.AvailableTimes[3:8], page.AvailableTimes)
+			assert.Equal(t, uint(len(all.AvailableTimes)), *page.Total)
+		}
+	})
+
+	t.Run("fail to get page with offset only", func(t *testing.T) {
+		today := time.Now().Format(rfc3339DateFormat)
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/available?from=%s&until=%s&offset=5", today, today)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+	})
+
 	t.Run("fail with invalid date format", func(t *testing.T) {
 		today := time.Now().Format(rfc3339DateFormat)
 		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/available?from=%s&until=INVALID", today)
@@ -66,13 +141,333 @@ func TestGetAvailableTireChangeTimes(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
 		assert.NotEmpty(t, result.Error)
 	})
+	t.Run("successfully get only Friday mornings", func(t *testing.T) {
+		for _, serviceType := range []string{"", "WHEEL_BALANCING"} {
+			reqURL := fmt.Sprintf(
+				v1Path+"/tire-change-times/available"+"?from=%s&until=%s&timeFrom=08:00&timeUntil=12:00&weekdays=FRIDAY&serviceType=%s",
+				time.Now().Format(rfc3339DateFormat),
+				time.Now().AddDate(0, 0, 14).Format(rfc3339DateFormat),
+				serviceType,
+			)
+
+			requestWriter := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+			router.ServeHTTP(requestWriter, req)
+
+			result := &tireChangeTimesResponse{}
+			unMarshal(t, requestWriter.Body.Bytes(), result)
+
+			assert.Equal(t, http.StatusOK, requestWriter.Code)
+			assert.NotEmpty(t, result.AvailableTimes)
+
+			for _, tireChangeTime := range result.AvailableTimes {
+				assert.Equal(t, time.Friday, tireChangeTime.Time.Local().Weekday())
+				assert.Less(t, tireChangeTime.Time.Local().Hour(), 12)
+			}
+		}
+	})
+
+	t.Run("successfully get tire change times of several weekdays", func(t *testing.T) {
+		reqURL := fmt.Sprintf(
+			v1Path+"/tire-change-times/available"+"?from=%s&until=%s&weekdays=MONDAY&weekdays=WEDNESDAY",
+			time.Now().Format(rfc3339DateFormat),
+			time.Now().AddDate(0, 0, 14).Format(rfc3339DateFormat),
+		)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotEmpty(t, result.AvailableTimes)
+
+		for _, tireChangeTime := range result.AvailableTimes {
+			assert.Contains(t, []time.Weekday{time.Monday, time.Wednesday}, tireChangeTime.Time.Local().Weekday())
+		}
+	})
+
+	t.Run("fail to get tire change times with invalid schedule", func(t *testing.T) {
+		for _, schedule := range []string{"timeFrom=8", "timeUntil=25:00", "weekdays=FRIDAYS"} {
+			reqURL := fmt.Sprintf(
+				v1Path+"/tire-change-times/available"+"?from=%s&until=%s&%s",
+				time.Now().Format(rfc3339DateFormat),
+				time.Now().AddDate(0, 0, 14).Format(rfc3339DateFormat),
+				schedule,
+			)
+
+			requestWriter := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+			router.ServeHTTP(requestWriter, req)
+
+			assert.Equal(t, http.StatusBadRequest, requestWriter.Code, schedule)
+		}
+	})
+
+}
+
+func TestGetTireChangeTime(t *testing.T) {
+	router := Init(true, Config{})
+
+	getTireChangeTime := func(reqURL string) *httptest.ResponseRecorder {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	t.Run("successfully get booked tire change time", func(t *testing.T) {
+		bookedTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(bookedTireChangeTime).Error)
+		bookTireChangeTime(t, bookedTireChangeTime, "TEST")
+
+		requestWriter := getTireChangeTime(v1Path + "/tire-change-times/" + bookedTireChangeTime.UUID)
+
+		result := &tireChangeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, bookedTireChangeTime.UUID, result.UUID)
+		assert.Equal(t, bookedTireChangeTime.Time.UTC(), result.Time)
+		assert.Equal(t, uint(0), result.RemainingCapacity)
+		assert.NotZero(t, result.Price)
+	})
+
+	t.Run("successfully get tire change time quoted for service and vehicle type", func(t *testing.T) {
+		availableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(availableTireChangeTime).Error)
+		reqURL := v1Path + "/tire-change-times/" + availableTireChangeTime.UUID
+
+		defaultQuote := &tireChangeBookingResponse{}
+		unMarshal(t, getTireChangeTime(reqURL).Body.Bytes(), defaultQuote)
+
+		requestWriter := getTireChangeTime(reqURL + "?serviceType=WHEEL_BALANCING&vehicleType=VAN")
+
+		result := &tireChangeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, uint(1), result.RemainingCapacity)
+		assert.Greater(t, result.Price, defaultQuote.Price)
+	})
+
+	t.Run("fail to get unknown tire change time", func(t *testing.T) {
+		requestWriter := getTireChangeTime(v1Path + "/tire-change-times/" + uuid.NewV4().String())
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		assert.Equal(t, http.StatusNotFound, result.StatusCode)
+	})
+
+	t.Run("fail to get tire change time with invalid UUID", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, getTireChangeTime(v1Path+"/tire-change-times/INVALID").Code)
+	})
+}
+
+func TestNextAvailableTireChangeTimes(t *testing.T) {
+	useLocalTimeZone(t, time.FixedZone("UTC-5", -5*60*60))
+	router := Init(true, Config{})
+	after := time.Now().AddDate(20, 0, 0).Truncate(time.Hour)
+
+	for i, available := range []bool{true, false, true, true} {
+		must(t, db.Create(newTireChangeTimeEntity(after.Add(time.Duration(i+1)*time.Hour), available)).Error)
+	}
+
+	getNextAvailable := func(query string) *httptest.ResponseRecorder {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/tire-change-times/next-available?"+query, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	t.Run("successfully get earliest available tire change times after given time", func(t *testing.T) {
+		requestWriter := getNextAvailable("count=2&after=" + after.UTC().Format(time.RFC3339))
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, result.AvailableTimes, 2)
+		assert.Equal(t, after.Add(time.Hour).UTC(), (result.AvailableTimes)[0].Time)
+		assert.Equal(t, after.Add(3*time.Hour).UTC(), (result.AvailableTimes)[1].Time)
+		assert.NotZero(t, (result.AvailableTimes)[0].Price)
+	})
+
+	t.Run("successfully get single earliest available tire change time by default", func(t *testing.T) {
+		requestWriter := getNextAvailable("")
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, result.AvailableTimes, 1)
+		assert.True(t, (result.AvailableTimes)[0].Time.After(time.Now()))
+	})
+
+	t.Run("successfully skip passed tire change times", func(t *testing.T) {
+		passed := newTireChangeTimeEntity(time.Now().Add(-time.Minute), true)
+		must(t, db.Create(passed).Error)
+
+		requestWriter := getNextAvailable("count=50&after=2006-01-02T15:04:05Z")
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, result.AvailableTimes, 50)
+
+		for _, tireChangeTime := range result.AvailableTimes {
+			assert.NotEqual(t, passed.UUID, tireChangeTime.UUID)
+			assert.True(t, tireChangeTime.Time.After(time.Now()))
+		}
+	})
+
+	t.Run("fail to get next available tire change times with invalid count", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, getNextAvailable("count=0").Code)
+		assert.Equal(t, http.StatusBadRequest, getNextAvailable("count=51").Code)
+	})
+
+	t.Run("fail to get next available tire change times with unknown vehicle type", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, getNextAvailable("vehicleType=TRUCK").Code)
+	})
+}
+
+func TestDailyAvailability(t *testing.T) {
+	router := Init(true, Config{AdminAPI: true})
+	year := time.Now().Year() + 21
+	day := time.Date(year, time.March, 1, 0, 0, 0, 0, time.Local)
+
+	partiallyBooked := newTireChangeTimeEntity(day.Add(10*time.Hour), true)
+	partiallyBooked.Capacity = 2
+	partiallyBooked.BookedCount = 1
+	restricted := newTireChangeTimeEntity(day.AddDate(0, 0, 1).Add(9*time.Hour), true)
+	restricted.VehicleTypes = ",VAN,"
+
+	for _, tireChangeTime := range []*tireChangeTimeEntity{
+		newTireChangeTimeEntity(day.Add(9*time.Hour), true),
+		partiallyBooked,
+		newTireChangeTimeEntity(day.Add(11*time.Hour), false),
+		restricted,
+		newTireChangeTimeEntity(day.AddDate(0, 0, 2).Add(9*time.Hour), true),
+	} {
+		must(t, db.Create(tireChangeTime).Error)
+	}
+
+	getDailyAvailability := func(query string) *httptest.ResponseRecorder {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/tire-change-times/daily-availability?"+query, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	t.Run("successfully count tire change times by days of the period", func(t *testing.T) {
+		requestWriter := getDailyAvailability(fmt.Sprintf("from=%d-03-01&until=%d-03-02", year, year))
+
+		result := &dailyAvailabilityResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, []*dayAvailabilityResponse{
+			{Date: fmt.Sprintf("%d-03-01", year), Total: 3, Available: 2, Booked: 1},
+			{Date: fmt.Sprintf("%d-03-02", year), Total: 1, Available: 1, Booked: 0},
+		}, []*dayAvailabilityResponse(result.Days))
+	})
+
+	t.Run("successfully count only tire change times able to service vehicle type", func(t *testing.T) {
+		requestWriter := getDailyAvailability(fmt.Sprintf("from=%d-03-02&until=%d-03-03&vehicleType=SUV", year, year))
+
+		result := &dailyAvailabilityResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, result.Days, 1)
+		assert.Equal(t, fmt.Sprintf("%d-03-03", year), (result.Days)[0].Date)
+	})
+
+	t.Run("fail to count tire change times of invalid period", func(t *testing.T) {
+		requestWriter := getDailyAvailability(fmt.Sprintf("from=%d-03-02&until=%d-03-01", year, year))
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+	})
+
+	t.Run("successfully count tire change times closed by mechanic absence neither available nor booked", func(t *testing.T) {
+		absenceDay := time.Date(year, time.April, 1, 0, 0, 0, 0, time.Local)
+
+		for absenceDay.Weekday() == time.Saturday || absenceDay.Weekday() == time.Sunday {
+			absenceDay = absenceDay.AddDate(0, 0, 1)
+		}
+
+		must(t, db.Create(newTireChangeTimeEntity(absenceDay.Add(10*time.Hour), true)).Error)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/admin/mechanics", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		mechanics := &mechanicsResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), mechanics)
+
+		for _, mechanic := range mechanics.Mechanics {
+			reqURL := fmt.Sprintf(v1Path+"/admin/mechanics/%s/absences", mechanic.UUID)
+			request := &mechanicAbsenceRequest{Date: absenceDay.Format(rfc3339DateFormat), Type: absenceTypeDayOff}
+			requestWriter = httptest.NewRecorder()
+			req, _ = http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+			router.ServeHTTP(requestWriter, req)
+			assert.Equal(t, http.StatusOK, requestWriter.Code)
+		}
+
+		date := absenceDay.Format(rfc3339DateFormat)
+		requestWriter = getDailyAvailability(fmt.Sprintf("from=%s&until=%s", date, date))
+
+		result := &dailyAvailabilityResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, []*dayAvailabilityResponse{
+			{Date: date, Total: 1, Available: 0, Booked: 0},
+		}, []*dayAvailabilityResponse(result.Days))
+	})
+
+	t.Run("successfully count passed tire change times of today only in total", func(t *testing.T) {
+		now := time.Now()
+		today := now.Format(rfc3339DateFormat)
+		getToday := func() *dayAvailabilityResponse {
+			result := &dailyAvailabilityResponse{}
+			unMarshal(t, getDailyAvailability(fmt.Sprintf("from=%s&until=%s", today, today)).Body.Bytes(), result)
+
+			if len(result.Days) > 0 {
+				return result.Days[0]
+			}
+
+			return &dayAvailabilityResponse{Date: today}
+		}
+
+		before := getToday()
+		passed := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+		must(t, db.Create(newTireChangeTimeEntity(passed, true)).Error)
+		after := getToday()
+
+		assert.Equal(t, before.Total+1, after.Total)
+		assert.Equal(t, before.Available, after.Available)
+		assert.Equal(t, before.Booked, after.Booked)
+	})
+
+	t.Run("fail to count tire change times without period", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, getDailyAvailability("").Code)
+	})
 }
 
 func TestTireChangeTimeBooking(t *testing.T) {
-	router := Init(true)
+	router := Init(true, Config{})
 
 	t.Run("successfully book available tire change time", func(t *testing.T) {
-		availableTireChangeTime := newTireChangeTimeEntity(time.Now(), true)
+		availableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
 		must(t, db.Create(availableTireChangeTime).Error)
 
 		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", availableTireChangeTime.UUID)
@@ -88,15 +483,15 @@ func TestTireChangeTimeBooking(t *testing.T) {
 		assert.Equal(t, http.StatusOK, requestWriter.Code)
 		assert.Equal(t, availableTireChangeTime.UUID, result.UUID)
 		assert.Equal(t, availableTireChangeTime.Time.UTC(), result.Time)
-		assert.Equal(t, request.ContactInformation, getTireChangeTime(t, availableTireChangeTime.UUID).BookedByContact)
+		assert.NotNil(t, getTireChangeTime(t, availableTireChangeTime.UUID).bookingOf(request.ContactInformation))
 		assert.False(t, getTireChangeTime(t, availableTireChangeTime.UUID).Available)
 	})
 
 	t.Run("successfully update already booked change time for same contact", func(t *testing.T) {
 		contactInformation := "TEST"
-		bookedTireChangeTime := newTireChangeTimeEntity(time.Now(), false)
-		bookedTireChangeTime.BookedByContact = contactInformation
+		bookedTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
 		must(t, db.Create(bookedTireChangeTime).Error)
+		bookTireChangeTime(t, bookedTireChangeTime, contactInformation)
 
 		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", bookedTireChangeTime.UUID)
 		request := &tireChangeBookingRequest{ContactInformation: contactInformation}
@@ -109,9 +504,9 @@ func TestTireChangeTimeBooking(t *testing.T) {
 	})
 
 	t.Run("fail to book unavailable tire change time", func(t *testing.T) {
-		unAvailableTireChangeTime := newTireChangeTimeEntity(time.Now(), false)
-		unAvailableTireChangeTime.BookedByContact = "some guy"
+		unAvailableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
 		must(t, db.Create(unAvailableTireChangeTime).Error)
+		bookTireChangeTime(t, unAvailableTireChangeTime, "some guy")
 
 		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", unAvailableTireChangeTime.UUID)
 		request := &tireChangeBookingRequest{ContactInformation: "another guy"}
@@ -123,6 +518,77 @@ func TestTireChangeTimeBooking(t *testing.T) {
 		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
 	})
 
+	t.Run("fail to book unavailable tire change time suggesting nearest alternatives", func(t *testing.T) {
+		requested := time.Now().AddDate(22, 0, 0).Truncate(time.Hour)
+		unAvailableTireChangeTime := newTireChangeTimeEntity(requested, false)
+		vanOnlyTireChangeTime := newTireChangeTimeEntity(requested.Add(-time.Hour), true)
+		vanOnlyTireChangeTime.VehicleTypes = ",VAN,"
+
+		for _, tireChangeTime := range []*tireChangeTimeEntity{
+			newTireChangeTimeEntity(requested.Add(-4*time.Hour), true),
+			newTireChangeTimeEntity(requested.Add(-3*time.Hour), true),
+			newTireChangeTimeEntity(requested.Add(-2*time.Hour), true),
+			vanOnlyTireChangeTime,
+			unAvailableTireChangeTime,
+			newTireChangeTimeEntity(requested.Add(time.Hour), false),
+			newTireChangeTimeEntity(requested.Add(2*time.Hour), true),
+			newTireChangeTimeEntity(requested.Add(3*time.Hour), true),
+			newTireChangeTimeEntity(requested.Add(4*time.Hour), true),
+		} {
+			must(t, db.Create(tireChangeTime).Error)
+		}
+
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", unAvailableTireChangeTime.UUID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Len(t, result.Alternatives, 4)
+
+		for i, offset := range []time.Duration{-3 * time.Hour, -2 * time.Hour, 2 * time.Hour, 3 * time.Hour} {
+			assert.Equal(t, requested.Add(offset).UTC(), result.Alternatives[i].Time)
+			assert.NotZero(t, result.Alternatives[i].Price)
+		}
+	})
+
+	t.Run("fail to book passed tire change time suggesting only upcoming alternatives", func(t *testing.T) {
+		requested := time.Now().AddDate(0, 0, -3)
+		passedTireChangeTime := newTireChangeTimeEntity(requested, false)
+
+		for _, tireChangeTime := range []*tireChangeTimeEntity{
+			passedTireChangeTime,
+			newTireChangeTimeEntity(requested.Add(time.Minute), true),
+			newTireChangeTimeEntity(requested.Add(2*time.Minute), true),
+			newTireChangeTimeEntity(time.Now().Add(time.Hour), true),
+			newTireChangeTimeEntity(time.Now().Add(2*time.Hour), true),
+		} {
+			must(t, db.Create(tireChangeTime).Error)
+		}
+
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", passedTireChangeTime.UUID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Len(t, result.Alternatives, 2)
+
+		for _, alternative := range result.Alternatives {
+			assert.True(t, alternative.Time.After(time.Now()))
+		}
+	})
+
 	t.Run("fail to book unknown tire change time", func(t *testing.T) {
 		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", uuid.NewV4().String())
 		request := &tireChangeBookingRequest{ContactInformation: "TEST"}
@@ -145,7 +611,7 @@ func TestTireChangeTimeBooking(t *testing.T) {
 	})
 
 	t.Run("fail to book with invalid request", func(t *testing.T) {
-		availableTireChangeTime := newTireChangeTimeEntity(time.Now(), true)
+		availableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
 		must(t, db.Create(availableTireChangeTime).Error)
 
 		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", availableTireChangeTime.UUID)
@@ -164,43 +630,1687 @@ func TestTireChangeTimeBooking(t *testing.T) {
 	})
 }
 
-func getTireChangeTime(t *testing.T, uuid string) *tireChangeTimeEntity {
-	var result tireChangeTimeEntity
+func TestTireChangeTimeCapacity(t *testing.T) {
+	router := Init(true, Config{BaysPerTimeSlot: 3})
 
-	if err := db.Model(tireChangeTimeEntity{}).Where("uuid = ?", uuid).Find(&result).Error; err != nil {
-		t.Fatalf("failed to fetch tire change time, error: %v", err)
+	book := func(tireChangeTime *tireChangeTimeEntity, contactInformation string) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", tireChangeTime.UUID)
+		request := &tireChangeBookingRequest{ContactInformation: contactInformation}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
 	}
 
-	return &result
-}
+	t.Run("successfully list seeded tire change times with configured capacity", func(t *testing.T) {
+		today := time.Now().Format(rfc3339DateFormat)
+		nextWeek := time.Now().AddDate(0, 0, 7).Format(rfc3339DateFormat)
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/available?from=%s&until=%s", today, nextWeek)
 
-func marshal(t *testing.T, value interface{}) io.Reader {
-	valueXML, e := xml.Marshal(value)
-	must(t, e)
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
 
-	return bytes.NewBuffer(valueXML)
-}
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
 
-func unMarshal(t *testing.T, rawXML []byte, result interface{}) {
-	if err := xml.Unmarshal(rawXML, result); err != nil {
-		t.Fatalf("failed to unmarshal XML, error: %v", err)
-	}
-}
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotEmpty(t, result.AvailableTimes)
 
-func verifyTireChangeTimesResponse(t *testing.T, result *tireChangeTimesResponse) {
-	assert.NotEmpty(t, result)
-	firstTime := result.AvailableTimes[0].Time
+		for _, availableTime := range result.AvailableTimes {
+			assert.Equal(t, uint(3), availableTime.RemainingCapacity)
+		}
+	})
 
-	for _, availableTime := range result.AvailableTimes {
-		tireChangeTimeEntity := getTireChangeTime(t, availableTime.UUID)
-		assert.NotNil(t, tireChangeTimeEntity)
-		assert.Equal(t, tireChangeTimeEntity.Time.UTC(), availableTime.Time)
-		assert.True(
-			t,
-			availableTime.Time.Equal(firstTime) || availableTime.Time.After(firstTime),
-			"response items should be in ascending order",
-		)
-	}
+	t.Run("successfully book tire change time until its capacity is used up", func(t *testing.T) {
+		tireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		tireChangeTime.Capacity = 2
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "first")
+		result := &tireChangeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, uint(1), result.RemainingCapacity)
+		assert.True(t, getTireChangeTime(t, tireChangeTime.UUID).Available)
+
+		requestWriter = book(tireChangeTime, "second")
+		result = &tireChangeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, uint(0), result.RemainingCapacity)
+		assert.False(t, getTireChangeTime(t, tireChangeTime.UUID).Available)
+		assert.Equal(t, uint(2), getTireChangeTime(t, tireChangeTime.UUID).BookedCount)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, book(tireChangeTime, "third").Code)
+	})
+
+	t.Run("successfully book concurrently without exceeding capacity", func(t *testing.T) {
+		tireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		tireChangeTime.Capacity = 2
+		must(t, db.Create(tireChangeTime).Error)
+
+		codes := make(chan int, 5)
+		wg := sync.WaitGroup{}
+
+		for i := 0; i < cap(codes); i++ {
+			wg.Add(1)
+
+			go func(contactInformation string) {
+				defer wg.Done()
+				codes <- book(tireChangeTime, contactInformation).Code
+			}(fmt.Sprintf("concurrent-%d", i))
+		}
+
+		wg.Wait()
+		close(codes)
+
+		booked := 0
+
+		for code := range codes {
+			if code == http.StatusOK {
+				booked++
+			} else {
+				assert.Equal(t, http.StatusUnprocessableEntity, code)
+			}
+		}
+
+		result := getTireChangeTime(t, tireChangeTime.UUID)
+
+		assert.Equal(t, 2, booked)
+		assert.Equal(t, uint(2), result.BookedCount)
+		assert.Len(t, result.Bookings, 2)
+		assert.False(t, result.Available)
+	})
+}
+
+func TestTireChangeTimeBookingLimit(t *testing.T) {
+	router := Init(true, Config{MaxActiveBookingsPerContact: 1})
+
+	book := func(tireChangeTime *tireChangeTimeEntity, contactInformation string) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", tireChangeTime.UUID)
+		request := &tireChangeBookingRequest{ContactInformation: contactInformation}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	firstTireChangeTime := newTireChangeTimeEntity(time.Now().AddDate(1, 0, 0), true)
+	secondTireChangeTime := newTireChangeTimeEntity(time.Now().AddDate(1, 0, 1), true)
+	must(t, db.Create(firstTireChangeTime).Error)
+	must(t, db.Create(secondTireChangeTime).Error)
+
+	t.Run("successfully book tire change time within limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, book(firstTireChangeTime, "limited contact").Code)
+	})
+
+	t.Run("successfully rebook already booked tire change time at limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, book(firstTireChangeTime, "limited contact").Code)
+	})
+
+	t.Run("fail to book tire change time over limit", func(t *testing.T) {
+		requestWriter := book(secondTireChangeTime, "limited contact")
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, result.StatusCode)
+		assert.NotEmpty(t, result.Error)
+		assert.True(t, getTireChangeTime(t, secondTireChangeTime.UUID).Available)
+	})
+
+	t.Run("successfully book tire change time for another contact", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, book(secondTireChangeTime, "another contact").Code)
+	})
+
+	t.Run("successfully book concurrently without exceeding limit", func(t *testing.T) {
+		tireChangeTimes := make([]*tireChangeTimeEntity, 5)
+		codes := make(chan int, len(tireChangeTimes))
+		wg := sync.WaitGroup{}
+
+		for i := range tireChangeTimes {
+			tireChangeTimes[i] = newTireChangeTimeEntity(time.Now().AddDate(1, 0, i+2), true)
+			must(t, db.Create(tireChangeTimes[i]).Error)
+		}
+
+		for _, tireChangeTime := range tireChangeTimes {
+			wg.Add(1)
+
+			go func(tireChangeTime *tireChangeTimeEntity) {
+				defer wg.Done()
+				codes <- book(tireChangeTime, "concurrent contact").Code
+			}(tireChangeTime)
+		}
+
+		wg.Wait()
+		close(codes)
+
+		booked := 0
+
+		for code := range codes {
+			if code == http.StatusOK {
+				booked++
+			}
+		}
+
+		available := 0
+
+		for _, tireChangeTime := range tireChangeTimes {
+			if getTireChangeTime(t, tireChangeTime.UUID).Available {
+				available++
+			}
+		}
+
+		assert.Equal(t, 1, booked)
+		assert.Equal(t, len(tireChangeTimes)-1, available)
+	})
+}
+
+func TestTireChangeTimeBookingWindow(t *testing.T) {
+	router := Init(true, Config{MinBookingLeadTime: 2 * time.Hour, MaxBookingAdvance: 30 * 24 * time.Hour})
+
+	book := func(tireChangeTime *tireChangeTimeEntity) *httptest.ResponseRecorder {
+		must(t, db.Create(tireChangeTime).Error)
+
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", tireChangeTime.UUID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	t.Run("successfully book tire change time within booking window", func(t *testing.T) {
+		requestWriter := book(newTireChangeTimeEntity(time.Now().Add(3*time.Hour), true))
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+	})
+
+	t.Run("fail to book passed tire change time", func(t *testing.T) {
+		requestWriter := book(newTireChangeTimeEntity(time.Now().AddDate(0, 0, -1), true))
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Contains(t, result.Error, "has already passed")
+	})
+
+	t.Run("fail to book tire change time too soon", func(t *testing.T) {
+		requestWriter := book(newTireChangeTimeEntity(time.Now().Add(time.Hour), true))
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Contains(t, result.Error, "must be booked at least")
+	})
+
+	t.Run("fail to book tire change time too far in the future", func(t *testing.T) {
+		requestWriter := book(newTireChangeTimeEntity(time.Now().AddDate(0, 0, 60), true))
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Contains(t, result.Error, "cannot be booked more than")
+	})
+
+	t.Run("fail to book unavailable tire change time suggesting alternatives within booking window", func(t *testing.T) {
+		requested := time.Now().Add(150 * time.Minute)
+		tooSoonTireChangeTime := newTireChangeTimeEntity(requested.Add(-time.Hour), true)
+		bookableTireChangeTime := newTireChangeTimeEntity(requested.Add(time.Minute), true)
+		must(t, db.Create(tooSoonTireChangeTime).Error)
+		must(t, db.Create(bookableTireChangeTime).Error)
+
+		requestWriter := book(newTireChangeTimeEntity(requested, false))
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+
+		var alternatives []string
+
+		for _, alternative := range result.Alternatives {
+			alternatives = append(alternatives, alternative.UUID)
+			assert.True(t, alternative.Time.After(time.Now().Add(2*time.Hour)))
+		}
+
+		assert.Contains(t, alternatives, bookableTireChangeTime.UUID)
+		assert.NotContains(t, alternatives, tooSoonTireChangeTime.UUID)
+	})
+
+}
+
+func TestServiceTypes(t *testing.T) {
+	router := Init(true, Config{})
+
+	book := func(tireChangeTime *tireChangeTimeEntity, serviceType string) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", tireChangeTime.UUID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST", ServiceType: serviceType}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	consecutiveTimes := func(start time.Time, amount int) []*tireChangeTimeEntity {
+		var tireChangeTimes []*tireChangeTimeEntity
+
+		for i := 0; i < amount; i++ {
+			tireChangeTime := newTireChangeTimeEntity(start.Add(time.Duration(i)*tireChangeTimeDuration), true)
+			must(t, db.Create(tireChangeTime).Error)
+			tireChangeTimes = append(tireChangeTimes, tireChangeTime)
+		}
+
+		return tireChangeTimes
+	}
+
+	t.Run("successfully list service types", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/service-types", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &serviceTypesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, result.ServiceTypes, 3)
+		assert.Equal(t, defaultServiceTypeCode, result.ServiceTypes[0].Code)
+		assert.Equal(t, uint(60), result.ServiceTypes[0].DurationMinutes)
+	})
+
+	t.Run("successfully list only start times fitting the service", func(t *testing.T) {
+		tomorrow := time.Now().AddDate(0, 0, 1)
+		start := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 22, 0, 0, 0, time.Local)
+		tireChangeTimes := consecutiveTimes(start, 2)
+
+		reqURL := fmt.Sprintf(
+			v1Path+"/tire-change-times/available?from=%s&until=%s&serviceType=WHEEL_BALANCING",
+			tomorrow.Format(rfc3339DateFormat),
+			tomorrow.AddDate(0, 0, 1).Format(rfc3339DateFormat),
+		)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		var listedUUIDs []string
+
+		for _, availableTime := range result.AvailableTimes {
+			listedUUIDs = append(listedUUIDs, availableTime.UUID)
+		}
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Contains(t, listedUUIDs, tireChangeTimes[0].UUID)
+		assert.NotContains(t, listedUUIDs, tireChangeTimes[1].UUID)
+	})
+
+	t.Run("successfully book and cancel service reserving consecutive tire change times", func(t *testing.T) {
+		tireChangeTimes := consecutiveTimes(time.Now().AddDate(0, 0, 3).Truncate(time.Hour).Add(time.Minute), 2)
+
+		requestWriter := book(tireChangeTimes[0], "WHEEL_BALANCING")
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.False(t, getTireChangeTime(t, tireChangeTimes[0].UUID).Available)
+		assert.False(t, getTireChangeTime(t, tireChangeTimes[1].UUID).Available)
+
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", tireChangeTimes[0].UUID)
+		request := &tireChangeBookingCancellationRequest{ContactInformation: "TEST"}
+
+		requestWriter = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.True(t, getTireChangeTime(t, tireChangeTimes[0].UUID).Available)
+		assert.True(t, getTireChangeTime(t, tireChangeTimes[1].UUID).Available)
+	})
+
+	t.Run("fail to book service not fitting before next tire change time is booked", func(t *testing.T) {
+		tireChangeTimes := consecutiveTimes(time.Now().AddDate(0, 0, 4).Truncate(time.Hour).Add(time.Minute), 2)
+		bookTireChangeTime(t, tireChangeTimes[1], "some guy")
+
+		requestWriter := book(tireChangeTimes[0], "WHEEL_BALANCING")
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.True(t, getTireChangeTime(t, tireChangeTimes[0].UUID).Available)
+	})
+
+	t.Run("fail to book service overlapping another booking of the contact", func(t *testing.T) {
+		tireChangeTimes := consecutiveTimes(time.Now().AddDate(0, 0, 6).Truncate(time.Hour).Add(time.Minute), 2)
+		bookTireChangeTime(t, tireChangeTimes[0], "TEST")
+
+		requestWriter := book(tireChangeTimes[0], "WHEEL_BALANCING")
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Contains(t, result.Error, "already booked by the contact")
+		assert.Empty(t, result.Alternatives)
+		assert.True(t, getTireChangeTime(t, tireChangeTimes[1].UUID).Available)
+		assert.Empty(t, getTireChangeTime(t, tireChangeTimes[1].UUID).Bookings)
+	})
+
+	t.Run("fail to book unknown service type", func(t *testing.T) {
+		tireChangeTimes := consecutiveTimes(time.Now().AddDate(0, 0, 5).Truncate(time.Hour).Add(time.Minute), 1)
+
+		requestWriter := book(tireChangeTimes[0], "UNKNOWN")
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.NotEmpty(t, result.Error)
+	})
+}
+
+func TestPriceQuotes(t *testing.T) {
+	router := Init(true, Config{})
+
+	book := func(tireChangeTime *tireChangeTimeEntity, vehicleType string) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", tireChangeTime.UUID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST", VehicleType: vehicleType}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	// off-season day at noon is free of surcharges
+	nextYear := time.Now().Year() + 1
+	weekday := time.Date(nextYear, time.June, 10, 12, 0, 0, 0, time.Local)
+
+	for weekday.Weekday() == time.Saturday || weekday.Weekday() == time.Sunday {
+		weekday = weekday.AddDate(0, 0, 1)
+	}
+
+	saturday := time.Date(nextYear, time.July, 1, 12, 0, 0, 0, time.Local)
+
+	for saturday.Weekday() != time.Saturday {
+		saturday = saturday.AddDate(0, 0, 1)
+	}
+
+	t.Run("successfully quote listed tire change times for vehicle type", func(t *testing.T) {
+		today := time.Now().Format(rfc3339DateFormat)
+		nextWeek := time.Now().AddDate(0, 0, 7).Format(rfc3339DateFormat)
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/available?from=%s&until=%s&vehicleType=SUV", today, nextWeek)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotEmpty(t, result.AvailableTimes)
+
+		serviceType := newServiceTypeRepository(db).oneByCode(defaultServiceTypeCode)
+
+		for _, availableTime := range result.AvailableTimes {
+			assert.Equal(t, pounds(newPricingEngine(DefaultPricing).quote(serviceType, "SUV", availableTime.Time)), availableTime.Price)
+		}
+	})
+
+	t.Run("successfully book tire change time with agreed price", func(t *testing.T) {
+		tireChangeTime := newTireChangeTimeEntity(weekday, true)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "SUV")
+		result := &tireChangeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, 48.0, result.Price)
+		assert.Equal(t, int64(4800), getTireChangeTime(t, tireChangeTime.UUID).bookingOf("TEST").PricePennies)
+		assert.Equal(t, "SUV", getTireChangeTime(t, tireChangeTime.UUID).bookingOf("TEST").VehicleType)
+	})
+
+	t.Run("successfully book weekend tire change time with premium", func(t *testing.T) {
+		tireChangeTime := newTireChangeTimeEntity(saturday, true)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "")
+		result := &tireChangeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, 50.0, result.Price)
+	})
+
+	t.Run("successfully quote peak hour surcharge by tire change time in server time zone", func(t *testing.T) {
+		useLocalTimeZone(t, time.FixedZone("UTC-5", -5*60*60))
+
+		// peak hour in server time zone stored in UTC is outside of peak hours
+		peakHour := time.Date(weekday.Year(), weekday.Month(), weekday.Day(), 16, 0, 0, 0, time.Local)
+		tireChangeTime := newTireChangeTimeEntity(peakHour.UTC(), true)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "")
+		result := &tireChangeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, 46.0, result.Price)
+	})
+
+	t.Run("fail to book for unknown vehicle type", func(t *testing.T) {
+		tireChangeTime := newTireChangeTimeEntity(weekday.Add(time.Hour), true)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "TRACTOR")
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.NotEmpty(t, result.Error)
+	})
+}
+
+func TestTireHotel(t *testing.T) {
+	router := Init(true, Config{})
+
+	registerTireSet := func(request *tireSetRequest) *httptest.ResponseRecorder {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, v1Path+"/tire-sets", marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	book := func(tireChangeTime *tireChangeTimeEntity, contactInformation string, tireSetUUID string) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", tireChangeTime.UUID)
+		request := &tireChangeBookingRequest{ContactInformation: contactInformation, TireSetUUID: tireSetUUID}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	attachTireSet := func(tireChangeTime *tireChangeTimeEntity, contactInformation string, tireSet *tireSetResponse) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking/tire-set", tireChangeTime.UUID)
+		request := &tireChangeBookingTireSetRequest{ContactInformation: contactInformation, TireSetUUID: tireSet.UUID}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	storedTireSet := &tireSetRequest{
+		ContactInformation: "TEST",
+		Size:               "205/55 R16",
+		Brand:              "Nokian",
+		Condition:          "GOOD",
+		StorageLocation:    "A-12",
+	}
+
+	t.Run("successfully register and fetch tire set", func(t *testing.T) {
+		requestWriter := registerTireSet(storedTireSet)
+		registered := &tireSetResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), registered)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, registered.UUID, 36)
+		assert.Equal(t, "A-12", registered.StorageLocation)
+
+		requestWriter = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/tire-sets/"+registered.UUID, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireSetResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, registered, result)
+
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, v1Path+"/tire-sets?contactInformation=TEST", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		contactTireSets := &tireSetsResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), contactTireSets)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Contains(t, contactTireSets.TireSets, registered)
+	})
+
+	t.Run("successfully book tire change time with stored tire set", func(t *testing.T) {
+		tireSet := &tireSetResponse{}
+		unMarshal(t, registerTireSet(storedTireSet).Body.Bytes(), tireSet)
+
+		tireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "TEST", tireSet.UUID)
+		result := &tireChangeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, tireSet.UUID, result.TireSetUUID)
+		assert.Equal(t, tireSet.UUID, getTireChangeTime(t, tireChangeTime.UUID).bookingOf("TEST").TireSetUUID)
+	})
+
+	t.Run("fail to book tire change time with tire set of another contact", func(t *testing.T) {
+		tireSet := &tireSetResponse{}
+		unMarshal(t, registerTireSet(storedTireSet).Body.Bytes(), tireSet)
+
+		tireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "another guy", tireSet.UUID)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.True(t, getTireChangeTime(t, tireChangeTime.UUID).Available)
+	})
+
+	t.Run("successfully attach stored tire set to booked tire change time", func(t *testing.T) {
+		tireSet := &tireSetResponse{}
+		unMarshal(t, registerTireSet(storedTireSet).Body.Bytes(), tireSet)
+
+		tireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(tireChangeTime).Error)
+		assert.Equal(t, http.StatusOK, book(tireChangeTime, "TEST", "").Code)
+
+		requestWriter := attachTireSet(tireChangeTime, "TEST", tireSet)
+		result := &tireChangeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, tireSet.UUID, result.TireSetUUID)
+		assert.Equal(t, tireSet.UUID, getTireChangeTime(t, tireChangeTime.UUID).bookingOf("TEST").TireSetUUID)
+	})
+
+	t.Run("fail to attach tire set to tire change time not booked by the contact", func(t *testing.T) {
+		tireSet := &tireSetResponse{}
+		unMarshal(t, registerTireSet(storedTireSet).Body.Bytes(), tireSet)
+
+		tireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(tireChangeTime).Error)
+		bookTireChangeTime(t, tireChangeTime, "another guy")
+
+		requestWriter := attachTireSet(tireChangeTime, "TEST", tireSet)
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Empty(t, getTireChangeTime(t, tireChangeTime.UUID).bookingOf("another guy").TireSetUUID)
+	})
+
+	t.Run("fail to register tire set with unknown condition", func(t *testing.T) {
+		request := *storedTireSet
+		request.Condition = "SHINY"
+
+		assert.Equal(t, http.StatusBadRequest, registerTireSet(&request).Code)
+	})
+
+	t.Run("fail to get unknown tire set", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/tire-sets/"+uuid.NewV4().String(), nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+	})
+}
+
+func TestWorkshop(t *testing.T) {
+	getWorkshop := func(config Config) (*httptest.ResponseRecorder, *workshopResponse) {
+		router := Init(true, config)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/workshop", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &workshopResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		return requestWriter, result
+	}
+
+	t.Run("successfully get default workshop information", func(t *testing.T) {
+		requestWriter, result := getWorkshop(Config{})
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, DefaultWorkshopInfo.Name, result.Name)
+		assert.Equal(t, DefaultWorkshopInfo.Latitude, result.Latitude)
+		assert.Equal(t, []string{"CAR", "SUV", "VAN"}, result.VehicleTypes)
+	})
+
+	t.Run("successfully get configured workshop information", func(t *testing.T) {
+		requestWriter, result := getWorkshop(Config{Workshop: WorkshopInfo{Name: "Soho tire workshop"}})
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, "Soho tire workshop", result.Name)
+		assert.Equal(t, DefaultWorkshopInfo.Address, result.Address)
+	})
+
+	t.Run("successfully get time zone of the server", func(t *testing.T) {
+		location, err := time.LoadLocation("America/New_York")
+		must(t, err)
+		useLocalTimeZone(t, location)
+
+		requestWriter, result := getWorkshop(Config{})
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, "America/New_York", result.TimeZone)
+	})
+}
+
+func TestContentNegotiation(t *testing.T) {
+	router := Init(true, Config{})
+
+	t.Run("successfully book tire change time with JSON request and response", func(t *testing.T) {
+		availableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(availableTireChangeTime).Error)
+
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", availableTireChangeTime.UUID)
+		requestJSON, err := json.Marshal(&tireChangeBookingRequest{ContactInformation: "TEST"})
+		must(t, err)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, bytes.NewBuffer(requestJSON))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeBookingResponse{}
+		must(t, json.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Contains(t, requestWriter.Header().Get("Content-Type"), "application/json")
+		assert.Equal(t, availableTireChangeTime.UUID, result.UUID)
+		assert.NotNil(t, getTireChangeTime(t, availableTireChangeTime.UUID).bookingOf("TEST"))
+	})
+
+	t.Run("successfully get XML response when Accept header is missing", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/workshop", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Contains(t, requestWriter.Header().Get("Content-Type"), "application/xml")
+	})
+
+	t.Run("successfully get error in JSON format", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", uuid.NewV4().String())
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, strings.NewReader(`{"contactInformation":"TEST"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		must(t, json.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, result.StatusCode)
+		assert.NotEmpty(t, result.Error)
+	})
+}
+
+func TestConditionalGet(t *testing.T) {
+	router := Init(true, Config{})
+	reqURL := fmt.Sprintf(
+		v1Path+"/tire-change-times/available?from=%s&until=%s",
+		time.Now().Format(rfc3339DateFormat),
+		time.Now().AddDate(0, 0, 7).Format(rfc3339DateFormat),
+	)
+
+	get := func(header string, value string) *httptest.ResponseRecorder {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	t.Run("successfully get tire change times with validators and cache control", func(t *testing.T) {
+		requestWriter := get("", "")
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotEmpty(t, requestWriter.Header().Get("ETag"))
+		assert.NotEmpty(t, requestWriter.Header().Get("Last-Modified"))
+		assert.Equal(t, "no-cache", requestWriter.Header().Get("Cache-Control"))
+	})
+
+	t.Run("successfully get not modified tire change times by entity tag", func(t *testing.T) {
+		etag := get("", "").Header().Get("ETag")
+
+		requestWriter := get("If-None-Match", etag)
+
+		assert.Equal(t, http.StatusNotModified, requestWriter.Code)
+		assert.Empty(t, requestWriter.Body.Bytes())
+	})
+
+	t.Run("successfully get not modified tire change times by modification time", func(t *testing.T) {
+		lastModified := get("", "").Header().Get("Last-Modified")
+
+		requestWriter := get("If-Modified-Since", lastModified)
+
+		assert.Equal(t, http.StatusNotModified, requestWriter.Code)
+		assert.Empty(t, requestWriter.Body.Bytes())
+	})
+
+	t.Run("successfully get changed tire change times", func(t *testing.T) {
+		etag := get("", "").Header().Get("ETag")
+		tomorrow := time.Now().AddDate(0, 0, 1)
+		tireChangeTime := newTireChangeTimeEntity(
+			time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 12, 1, 0, 0, time.Local),
+			true,
+		)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := get("If-None-Match", etag)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotEqual(t, etag, requestWriter.Header().Get("ETag"))
+		assert.NotEmpty(t, requestWriter.Body.Bytes())
+	})
+
+	t.Run("successfully get tire change times modified since given time", func(t *testing.T) {
+		requestWriter := get("If-Modified-Since", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+	})
+}
+
+func TestProblemDetails(t *testing.T) {
+	router := Init(true, Config{})
+
+	t.Run("successfully describe invalid request by problem details in JSON", func(t *testing.T) {
+		availableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(availableTireChangeTime).Error)
+
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", availableTireChangeTime.UUID)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, strings.NewReader("<tireChangeBookingRequest/>"))
+		req.Header.Set("Content-Type", "application/xml")
+		req.Header.Set("Accept", "application/problem+json")
+		req.Header.Set(shared.TraceIDHeader, "trace-1")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &shared.Problem{}
+		must(t, json.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.Contains(t, requestWriter.Header().Get("Content-Type"), shared.MIMEProblemJSON)
+		assert.Equal(t, shared.ProblemTypeURNPrefix+"invalid-request", result.Type)
+		assert.Equal(t, "Request is invalid", result.Title)
+		assert.Equal(t, http.StatusBadRequest, result.Status)
+		assert.Equal(t, reqURL, result.Instance)
+		assert.Equal(t, "trace-1", result.TraceID)
+		assert.Equal(t, []*shared.InvalidParam{
+			{Name: "contactInformation", Reason: `property "contactInformation" is missing`},
+		}, result.InvalidParams)
+	})
+
+	t.Run("successfully describe unknown resource by problem details in XML", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/tire-sets/"+uuid.NewV4().String(), nil)
+		req.Header.Set("Accept", "application/problem+xml")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &shared.Problem{}
+		must(t, xml.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		assert.Contains(t, requestWriter.Header().Get("Content-Type"), shared.MIMEProblemXML)
+		assert.Equal(t, shared.ProblemTypeURNPrefix+"unknown-tire-set", result.Type)
+		assert.Equal(t, http.StatusNotFound, result.Status)
+		assert.NotEmpty(t, result.Detail)
+		assert.NotEmpty(t, result.TraceID)
+	})
+
+	t.Run("successfully suggest alternatives by problem details extension member", func(t *testing.T) {
+		requested := time.Now().AddDate(23, 0, 0).Truncate(time.Hour)
+		unAvailableTireChangeTime := newTireChangeTimeEntity(requested, false)
+		alternativeTireChangeTime := newTireChangeTimeEntity(requested.Add(time.Hour), true)
+		must(t, db.Create(unAvailableTireChangeTime).Error)
+		must(t, db.Create(alternativeTireChangeTime).Error)
+
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", unAvailableTireChangeTime.UUID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		req.Header.Set("Accept", "application/problem+json")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &struct {
+			shared.Problem
+			Alternatives []*tireChangeBookingResponse `json:"alternatives"`
+		}{}
+		must(t, json.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, shared.ProblemTypeURNPrefix+"unavailable-tire-change-time", result.Type)
+		assert.NotEmpty(t, result.Alternatives)
+		assert.Equal(t, alternativeTireChangeTime.UUID, result.Alternatives[len(result.Alternatives)-1].UUID)
+	})
+
+	t.Run("successfully tell service not fitting apart from unavailable tire change time", func(t *testing.T) {
+		requested := time.Now().AddDate(23, 0, 1).Truncate(time.Hour)
+		availableTireChangeTime := newTireChangeTimeEntity(requested, true)
+		must(t, db.Create(availableTireChangeTime).Error)
+
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", availableTireChangeTime.UUID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST", ServiceType: "WHEEL_BALANCING"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		req.Header.Set("Accept", "application/problem+json")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &shared.Problem{}
+		must(t, json.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, shared.ProblemTypeURNPrefix+"service-does-not-fit", result.Type)
+		assert.Nil(t, result.Alternatives)
+	})
+
+	t.Run("successfully name invalid params by request keeping validation messages", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest(http.MethodGet, v1Path, nil)
+
+		err := binding.Validator.ValidateStruct(&tireChangeBookingRequest{})
+		problem := shared.NewProblem(c, "invalid-request", "Request is invalid", http.StatusBadRequest, err)
+
+		assert.Contains(t, err.Error(), "'ContactInformation'")
+		assert.Equal(t, "contactInformation", problem.InvalidParams[0].Name)
+	})
+
+	t.Run("successfully keep error format unless problem details are accepted explicitly", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/tire-sets/"+uuid.NewV4().String(), nil)
+		req.Header.Set("Accept", "*/*")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		assert.NotEmpty(t, result.Error)
+	})
+}
+
+func TestVehicleTypeRestrictions(t *testing.T) {
+	router := Init(true, Config{})
+
+	nextYear := time.Now().AddDate(1, 0, 0)
+	day := time.Date(nextYear.Year(), nextYear.Month(), nextYear.Day(), 0, 0, 0, 0, time.Local)
+	restrictedTireChangeTime := newTireChangeTimeEntity(day.Add(20*time.Hour), true)
+	restrictedTireChangeTime.VehicleTypes = ",CAR,SUV,"
+	must(t, db.Create(restrictedTireChangeTime).Error)
+
+	listUUIDs := func(vehicleType string) []string {
+		reqURL := fmt.Sprintf(
+			v1Path+"/tire-change-times/available?from=%s&until=%s&vehicleType=%s",
+			day.Format(rfc3339DateFormat),
+			day.AddDate(0, 0, 1).Format(rfc3339DateFormat),
+			vehicleType,
+		)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+
+		var uuids []string
+
+		for _, availableTime := range result.AvailableTimes {
+			uuids = append(uuids, availableTime.UUID)
+
+			if availableTime.UUID == restrictedTireChangeTime.UUID {
+				assert.Equal(t, []string{"CAR", "SUV"}, availableTime.VehicleTypes)
+			}
+		}
+
+		return uuids
+	}
+
+	t.Run("successfully list only tire change times supporting vehicle type", func(t *testing.T) {
+		assert.Contains(t, listUUIDs("SUV"), restrictedTireChangeTime.UUID)
+		assert.NotContains(t, listUUIDs("VAN"), restrictedTireChangeTime.UUID)
+	})
+
+	t.Run("fail to book tire change time for unsupported vehicle type", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", restrictedTireChangeTime.UUID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST", VehicleType: "VAN"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Contains(t, result.Error, "VAN")
+		assert.True(t, getTireChangeTime(t, restrictedTireChangeTime.UUID).Available)
+	})
+}
+
+func TestMechanicShifts(t *testing.T) {
+	router := Init(true, Config{BaysPerTimeSlot: 2, AdminAPI: true})
+
+	nextYear := time.Now().AddDate(1, 0, 0)
+	day := time.Date(nextYear.Year(), nextYear.Month(), nextYear.Day(), 0, 0, 0, 0, time.Local)
+
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	tireChangeTime := newTireChangeTimeEntity(day.Add(10*time.Hour+time.Minute), true)
+	tireChangeTime.Capacity = 2
+	must(t, db.Create(tireChangeTime).Error)
+	bookTireChangeTime(t, tireChangeTime, "FIRST")
+	bookTireChangeTime(t, tireChangeTime, "SECOND")
+
+	getMechanics := func() *mechanicsResponse {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/admin/mechanics", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &mechanicsResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+
+		return result
+	}
+
+	getRescheduledBookings := func() *rescheduledBookingsResponse {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/admin/rescheduled-bookings", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &rescheduledBookingsResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+
+		return result
+	}
+
+	mechanics := getMechanics().Mechanics
+	var absenceUUID string
+
+	t.Run("successfully list mechanic operating every bay on weekdays", func(t *testing.T) {
+		assert.Len(t, mechanics, 2)
+		assert.Len(t, mechanics[0].Shifts, 5)
+		assert.Equal(t, &mechanicShiftResponse{Weekday: "MONDAY", StartHour: 8, EndHour: 17}, mechanics[0].Shifts[0])
+	})
+
+	t.Run("successfully flag latest booking for rescheduling when mechanic calls in sick", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v1Path+"/admin/mechanics/%s/absences", mechanics[0].UUID)
+		request := &mechanicAbsenceRequest{Date: day.Format(rfc3339DateFormat), Type: absenceTypeSickLeave}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &mechanicAbsenceResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+		absenceUUID = result.UUID
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, absenceTypeSickLeave, result.Type)
+		assert.Len(t, result.RescheduledBookings, 1)
+		assert.Equal(t, "SECOND", result.RescheduledBookings[0].ContactInformation)
+		assert.Equal(t, tireChangeTime.UUID, result.RescheduledBookings[0].TireChangeTimeUUID)
+		assert.Equal(t, uint(1), getTireChangeTime(t, tireChangeTime.UUID).Capacity)
+
+		rescheduled := getRescheduledBookings().Bookings
+		assert.Len(t, rescheduled, 1)
+		assert.Equal(t, "SECOND", rescheduled[0].ContactInformation)
+	})
+
+	t.Run("successfully restore bookings when substitute mechanic takes the shift", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, v1Path+"/admin/mechanics", marshal(t, &mechanicRequest{Name: "Substitute"}))
+		router.ServeHTTP(requestWriter, req)
+
+		substitute := &mechanicResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), substitute)
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Empty(t, substitute.Shifts)
+
+		reqURL := fmt.Sprintf(v1Path+"/admin/mechanics/%s/shifts", substitute.UUID)
+		request := &mechanicShiftsRequest{Shifts: []*mechanicShiftRequest{
+			{Weekday: strings.ToUpper(day.Weekday().String()), StartHour: 10, EndHour: 12},
+		}}
+
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &mechanicResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, result.Shifts, 1)
+		assert.Equal(t, uint(2), getTireChangeTime(t, tireChangeTime.UUID).Capacity)
+		assert.Empty(t, getRescheduledBookings().Bookings)
+	})
+
+	t.Run("successfully cancel absence of the mechanic", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v1Path+"/admin/mechanics/%s/absences/%s", mechanics[0].UUID, absenceUUID)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &mechanicResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Empty(t, result.Absences)
+		assert.Equal(t, uint(2), getTireChangeTime(t, tireChangeTime.UUID).Capacity)
+	})
+
+	t.Run("successfully staff tire change time stored in UTC by shifts in server time zone", func(t *testing.T) {
+		useLocalTimeZone(t, time.FixedZone("UTC-5", -5*60*60))
+
+		// end of the shift in server time zone is outside of working hours in UTC
+		eveningTireChangeTime := newTireChangeTimeEntity(
+			time.Date(day.Year(), day.Month(), day.Day(), 16, 0, 0, 0, time.Local).UTC(),
+			true,
+		)
+		must(t, db.Create(eveningTireChangeTime).Error)
+
+		reqURL := fmt.Sprintf(v1Path+"/admin/mechanics/%s/shifts", mechanics[1].UUID)
+		request := &mechanicShiftsRequest{Shifts: []*mechanicShiftRequest{
+			{Weekday: strings.ToUpper(day.Weekday().String()), StartHour: 8, EndHour: 17},
+		}}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, uint(2), getTireChangeTime(t, eveningTireChangeTime.UUID).Capacity)
+	})
+
+	t.Run("fail to set shift ending before it starts", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v1Path+"/admin/mechanics/%s/shifts", mechanics[0].UUID)
+		request := &mechanicShiftsRequest{Shifts: []*mechanicShiftRequest{
+			{Weekday: "MONDAY", StartHour: 12, EndHour: 10},
+		}}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+	})
+
+	t.Run("fail to register absence of unknown mechanic", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v1Path+"/admin/mechanics/%s/absences", uuid.NewV4().String())
+		request := &mechanicAbsenceRequest{Date: day.Format(rfc3339DateFormat), Type: absenceTypeDayOff}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+	})
+
+	t.Run("fail to cancel unknown absence of the mechanic", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v1Path+"/admin/mechanics/%s/absences/%s", mechanics[0].UUID, uuid.NewV4().String())
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		assert.Contains(t, result.Error, "absence")
+	})
+
+	t.Run("fail to manage mechanics unless admin API is enabled", func(t *testing.T) {
+		router := Init(true, Config{})
+
+		for _, path := range []string{v1Path + "/admin/mechanics", v2Path + "/admin/mechanics"} {
+			requestWriter := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			router.ServeHTTP(requestWriter, req)
+
+			assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		}
+	})
+}
+
+func TestBranches(t *testing.T) {
+	router := Init(true, Config{
+		Branches: []Config{{
+			BranchID: "camden",
+			Workshop: WorkshopInfo{Name: "London Camden"},
+			Pricing:  Pricing{VehicleTypeFactors: map[string]int64{defaultVehicleType: 100, "VAN": 200}},
+		}},
+	})
+
+	camdenPath := "/branches/camden" + v1Path
+	from := time.Now().AddDate(0, 0, 1).Format(rfc3339DateFormat)
+	until := time.Now().AddDate(0, 0, 14).Format(rfc3339DateFormat)
+
+	getAvailable := func(path string) *tireChangeTimesResponse {
+		reqURL := fmt.Sprintf(path+"/tire-change-times/available?from=%s&until=%s", from, until)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+
+		return result
+	}
+
+	t.Run("successfully list branches hosted by the server", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/branches", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &branchesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, result.Branches, 2)
+		assert.Equal(t, "camden", result.Branches[0].ID)
+		assert.Equal(t, "London Camden", result.Branches[0].Workshop.Name)
+		assert.Equal(t, DefaultBranchID, result.Branches[1].ID)
+		assert.Equal(t, DefaultWorkshopInfo.Name, result.Branches[1].Workshop.Name)
+	})
+
+	t.Run("successfully serve default branch by api routes", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/branches/"+DefaultBranchID+v1Path+"/workshop", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &workshopResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, DefaultWorkshopInfo.Name, result.Name)
+		assert.Equal(t, getAvailable(v1Path), getAvailable("/branches/"+DefaultBranchID+v1Path))
+	})
+
+	t.Run("successfully book tire change time of single branch", func(t *testing.T) {
+		tireChangeTime := getAvailable(camdenPath).AvailableTimes[0]
+		reqURL := fmt.Sprintf(camdenPath+"/tire-change-times/%s/booking", tireChangeTime.UUID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotEqual(t, tireChangeTime.Time, getAvailable(camdenPath).AvailableTimes[0].Time)
+		assert.Equal(t, tireChangeTime.Time, getAvailable(v1Path).AvailableTimes[0].Time)
+	})
+
+	t.Run("successfully quote tire change times by price list of the branch", func(t *testing.T) {
+		reqURL := fmt.Sprintf(camdenPath+"/tire-change-times/available?from=%s&until=%s&vehicleType=VAN", from, until)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotEmpty(t, result.AvailableTimes)
+
+		for _, availableTime := range result.AvailableTimes {
+			assert.Equal(t, 80.0, availableTime.Price)
+		}
+
+		reqURL = fmt.Sprintf(camdenPath+"/tire-change-times/available?from=%s&until=%s&vehicleType=SUV", from, until)
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+	})
+
+	t.Run("fail to access unknown branch", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/branches/croydon"+v1Path+"/workshop", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		assert.Contains(t, result.Error, "croydon")
+	})
+}
+
+func TestWaitlist(t *testing.T) {
+	router := Init(true, Config{})
+
+	t.Run("successfully assign cancelled tire change time to waitlisted contact", func(t *testing.T) {
+		bookedTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(bookedTireChangeTime).Error)
+		bookTireChangeTime(t, bookedTireChangeTime, "some guy")
+
+		request := &waitlistRequest{ContactInformation: "another guy", TireChangeTimeUUID: bookedTireChangeTime.UUID}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, v1Path+"/waitlist", marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		entry := &waitlistEntryResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), entry)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, waitlistStatusWaiting, entry.Status)
+		assert.Nil(t, entry.AssignedTime)
+
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", bookedTireChangeTime.UUID)
+		cancellation := &tireChangeBookingCancellationRequest{ContactInformation: "some guy"}
+
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodDelete, reqURL, marshal(t, cancellation))
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotNil(t, getTireChangeTime(t, bookedTireChangeTime.UUID).bookingOf("another guy"))
+		assert.False(t, getTireChangeTime(t, bookedTireChangeTime.UUID).Available)
+
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, v1Path+"/waitlist/"+entry.UUID, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &waitlistEntryResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, waitlistStatusAssigned, result.Status)
+		assert.Equal(t, bookedTireChangeTime.UUID, result.AssignedTime.UUID)
+	})
+
+	t.Run("successfully assign free tire change time of requested day right away", func(t *testing.T) {
+		day := time.Now().AddDate(1, 0, 0)
+		availableTireChangeTime := newTireChangeTimeEntity(day, true)
+		must(t, db.Create(availableTireChangeTime).Error)
+
+		request := &waitlistRequest{ContactInformation: "TEST", Date: day.Format(rfc3339DateFormat)}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, v1Path+"/waitlist", marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &waitlistEntryResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, waitlistStatusAssigned, result.Status)
+		assert.Equal(t, availableTireChangeTime.UUID, result.AssignedTime.UUID)
+		assert.NotNil(t, getTireChangeTime(t, availableTireChangeTime.UUID).bookingOf("TEST"))
+	})
+
+	t.Run("successfully assign cancelled tire change time to contact waitlisted for its day", func(t *testing.T) {
+		useLocalTimeZone(t, time.FixedZone("UTC-5", -5*60*60))
+
+		// late evening in server time zone stored in UTC falls on the next day
+		day := time.Now().AddDate(1, 0, 7)
+		bookedTireChangeTime := newTireChangeTimeEntity(
+			time.Date(day.Year(), day.Month(), day.Day(), 22, 0, 0, 0, time.Local).UTC(),
+			true,
+		)
+		must(t, db.Create(bookedTireChangeTime).Error)
+		bookTireChangeTime(t, bookedTireChangeTime, "some guy")
+
+		request := &waitlistRequest{ContactInformation: "day guy", Date: day.Format(rfc3339DateFormat)}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, v1Path+"/waitlist", marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		entry := &waitlistEntryResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), entry)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, waitlistStatusWaiting, entry.Status)
+
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", bookedTireChangeTime.UUID)
+		cancellation := &tireChangeBookingCancellationRequest{ContactInformation: "some guy"}
+
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodDelete, reqURL, marshal(t, cancellation))
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotNil(t, getTireChangeTime(t, bookedTireChangeTime.UUID).bookingOf("day guy"))
+	})
+
+	t.Run("fail to cancel tire change time booked by another contact", func(t *testing.T) {
+		bookedTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(bookedTireChangeTime).Error)
+		bookTireChangeTime(t, bookedTireChangeTime, "some guy")
+
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", bookedTireChangeTime.UUID)
+		request := &tireChangeBookingCancellationRequest{ContactInformation: "another guy"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.NotNil(t, getTireChangeTime(t, bookedTireChangeTime.UUID).bookingOf("some guy"))
+	})
+
+	t.Run("fail to register without tire change time or date", func(t *testing.T) {
+		request := &waitlistRequest{ContactInformation: "TEST"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, v1Path+"/waitlist", marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.NotEmpty(t, result.Error)
+	})
+
+	t.Run("fail to get unknown waitlist entry", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/waitlist/"+uuid.NewV4().String(), nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+	})
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	router := Init(true, Config{})
+
+	t.Run("successfully serve OpenAPI document in YAML and JSON formats", func(t *testing.T) {
+		for path, contentType := range map[string]string{
+			"/openapi.yaml":    shared.MIMEYAML,
+			"/openapi.json":    "application/json",
+			"/openapi-v2.yaml": shared.MIMEYAML,
+			"/openapi-v2.json": "application/json",
+		} {
+			requestWriter := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			router.ServeHTTP(requestWriter, req)
+
+			assert.Equal(t, http.StatusOK, requestWriter.Code)
+			assert.Contains(t, requestWriter.Header().Get("Content-Type"), contentType)
+			assert.Contains(t, requestWriter.Body.String(), "London tire workshop API")
+		}
+	})
+
+	t.Run("fail to book tire change time by request body not conforming to OpenAPI document", func(t *testing.T) {
+		availableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(availableTireChangeTime).Error)
+
+		reqURL := fmt.Sprintf(v1Path+"/tire-change-times/%s/booking", availableTireChangeTime.UUID)
+		body := "<tireChangeBookingRequest><contactInformation>some guy</contactInformation>" +
+			"<tireSetUuid>unknown</tireSetUuid></tireChangeBookingRequest>"
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, strings.NewReader(body))
+		req.Header.Set("Accept", "application/problem+xml")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &shared.Problem{}
+		must(t, xml.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.Len(t, result.InvalidParams, 1)
+		assert.Equal(t, "tireSetUuid", result.InvalidParams[0].Name)
+		assert.True(t, getTireChangeTime(t, availableTireChangeTime.UUID).Available)
+	})
+
+	t.Run("fail to respond by route not described by OpenAPI document", func(t *testing.T) {
+		router.GET(v1Path+"/undocumented", func(ctx *gin.Context) { ctx.String(http.StatusOK, "undocumented") })
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/undocumented", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusInternalServerError, requestWriter.Code)
+		assert.Contains(t, requestWriter.Body.String(), "does not conform to OpenAPI document")
+	})
+}
+
+func TestV2API(t *testing.T) {
+	router := Init(true, Config{})
+	day := time.Now().AddDate(1, 0, 0)
+
+	for day.Weekday() != time.Wednesday {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	var tireChangeTimes []*tireChangeTimeEntity
+
+	for _, hour := range []int{9, 10, 11} {
+		tireChangeTime := newTireChangeTimeEntity(time.Date(day.Year(), day.Month(), day.Day(), hour, 0, 0, 0, time.Local), true)
+		must(t, db.Create(tireChangeTime).Error)
+		tireChangeTimes = append(tireChangeTimes, tireChangeTime)
+	}
+
+	getPage := func(query string) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(
+			v2Path+"/tire-change-times?from=%s&until=%s&%s",
+			day.Format(rfc3339DateFormat),
+			day.AddDate(0, 0, 1).Format(rfc3339DateFormat),
+			query,
+		)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	t.Run("successfully get first page of available tire change times in JSON", func(t *testing.T) {
+		requestWriter := getPage("limit=2")
+
+		result := &tireChangeTimesPageResponse{}
+		must(t, json.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Contains(t, requestWriter.Header().Get("Content-Type"), "application/json")
+		assert.Equal(t, uint(3), result.Total)
+		assert.Equal(t, uint(2), result.Limit)
+		assert.Equal(t, uint(0), result.Offset)
+		assert.Len(t, result.Items, 2)
+		assert.Equal(t, tireChangeTimes[0].UUID, result.Items[0].UUID)
+		assert.Contains(t, requestWriter.Header().Get("Link"), `rel="next"`)
+		assert.Contains(t, requestWriter.Header().Get("Link"), "offset=2")
+		assert.NotContains(t, requestWriter.Header().Get("Link"), `rel="prev"`)
+		assert.Empty(t, requestWriter.Header().Get("Deprecation"))
+	})
+
+	t.Run("successfully get last page of available tire change times", func(t *testing.T) {
+		requestWriter := getPage("limit=2&offset=2")
+
+		result := &tireChangeTimesPageResponse{}
+		must(t, json.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, result.Items, 1)
+		assert.Equal(t, tireChangeTimes[2].UUID, result.Items[0].UUID)
+		assert.Contains(t, requestWriter.Header().Get("Link"), `rel="prev"`)
+		assert.NotContains(t, requestWriter.Header().Get("Link"), `rel="next"`)
+	})
+
+	t.Run("fail to get page exceeding maximum limit", func(t *testing.T) {
+		requestWriter := getPage("limit=101")
+
+		result := &errorResponse{}
+		must(t, json.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+	})
+
+	t.Run("successfully book tire change time by JSON body", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%s/booking", tireChangeTimes[0].UUID)
+		request := &tireChangeBookingRequest{ContactInformation: "some guy"}
+		body, _ := json.Marshal(request)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, bytes.NewReader(body))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeBookingResponse{}
+		must(t, json.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, tireChangeTimes[0].UUID, result.UUID)
+		assert.False(t, getTireChangeTime(t, tireChangeTimes[0].UUID).Available)
+	})
+
+	t.Run("fail to book tire change time by XML body", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%s/booking", tireChangeTimes[1].UUID)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, &tireChangeBookingRequest{ContactInformation: "some guy"}))
+		req.Header.Set("Content-Type", "application/xml")
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.True(t, getTireChangeTime(t, tireChangeTimes[1].UUID).Available)
+	})
+
+	t.Run("successfully leave v1 routes without deprecation unless configured", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/workshop", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Empty(t, requestWriter.Header().Get("Deprecation"))
+		assert.Empty(t, requestWriter.Header().Get("Sunset"))
+		assert.Empty(t, requestWriter.Header().Get("Link"))
+	})
+
+	t.Run("successfully announce deprecation of v1 routes", func(t *testing.T) {
+		deprecation := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+		router := Init(true, Config{V1Deprecation: deprecation})
+
+		for path, successor := range map[string]string{
+			v1Path + "/workshop":                    v2Path + "/workshop",
+			"/branches/main" + v1Path + "/workshop": "/branches/main" + v2Path + "/workshop",
+		} {
+			requestWriter := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			router.ServeHTTP(requestWriter, req)
+
+			assert.Equal(t, http.StatusOK, requestWriter.Code)
+			assert.Equal(t, fmt.Sprintf("@%d", deprecation.Unix()), requestWriter.Header().Get("Deprecation"))
+			assert.Empty(t, requestWriter.Header().Get("Sunset"))
+			assert.Equal(t, fmt.Sprintf(`<%s>; rel="successor-version"`, successor), requestWriter.Header().Get("Link"))
+		}
+	})
+
+	t.Run("successfully announce configured deprecation of v1 routes", func(t *testing.T) {
+		deprecation := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+		sunset := time.Date(2027, time.July, 1, 0, 0, 0, 0, time.UTC)
+		router := Init(true, Config{V1Deprecation: deprecation, V1Sunset: sunset})
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v1Path+"/tire-change-times/available?from=2006-01-02&until=2006-01-09", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, fmt.Sprintf("@%d", deprecation.Unix()), requestWriter.Header().Get("Deprecation"))
+		assert.Equal(t, sunset.Format(http.TimeFormat), requestWriter.Header().Get("Sunset"))
+		assert.Equal(
+			t,
+			fmt.Sprintf(`<%s>; rel="successor-version"`, v2Path+"/tire-change-times"),
+			requestWriter.Header().Get("Link"),
+		)
+	})
+}
+
+func bookTireChangeTime(t *testing.T, tireChangeTime *tireChangeTimeEntity, contactInformation string) {
+	must(t, tireChangeTime.makeBooking(
+		newTireChangeBookingEntity(tireChangeTime, contactInformation, defaultServiceTypeCode, defaultVehicleType, 40),
+	))
+	must(t, db.Save(tireChangeTime).Error)
+}
+
+func getTireChangeTime(t *testing.T, uuid string) *tireChangeTimeEntity {
+	var result tireChangeTimeEntity
+
+	if err := db.Model(tireChangeTimeEntity{}).Preload("Bookings").Where("uuid = ?", uuid).Find(&result).Error; err != nil {
+		t.Fatalf("failed to fetch tire change time, error: %v", err)
+	}
+
+	return &result
+}
+
+func marshal(t *testing.T, value interface{}) io.Reader {
+	valueXML, e := xml.Marshal(value)
+	must(t, e)
+
+	return bytes.NewBuffer(valueXML)
+}
+
+func unMarshal(t *testing.T, rawXML []byte, result interface{}) {
+	if err := xml.Unmarshal(rawXML, result); err != nil {
+		t.Fatalf("failed to unmarshal XML, error: %v", err)
+	}
+}
+
+func verifyTireChangeTimesResponse(t *testing.T, result *tireChangeTimesResponse) {
+	assert.NotEmpty(t, result)
+	firstTime := result.AvailableTimes[0].Time
+
+	for _, availableTime := range result.AvailableTimes {
+		tireChangeTimeEntity := getTireChangeTime(t, availableTime.UUID)
+		assert.NotNil(t, tireChangeTimeEntity)
+		assert.Equal(t, tireChangeTimeEntity.Time.UTC(), availableTime.Time)
+		assert.True(
+			t,
+			availableTime.Time.Equal(firstTime) || availableTime.Time.After(firstTime),
+			"response items should be in ascending order",
+		)
+	}
+}
+
+// useLocalTimeZone runs the test in given server time zone, the original time zone is restored by test cleanup
+func useLocalTimeZone(t *testing.T, location *time.Location) {
+	original := time.Local
+	time.Local = location
+	t.Cleanup(func() { time.Local = original })
 }
 
 func must(t *testing.T, err error) {
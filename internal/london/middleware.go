@@ -1,10 +1,14 @@
 package london
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
+	"github.com/surmus/tire-change-workshop/internal/shared"
 	"net/http"
 	"runtime/debug"
+	"strings"
+	"time"
 )
 
 func errorHandlerMiddleware() gin.HandlerFunc {
@@ -14,7 +18,26 @@ func errorHandlerMiddleware() gin.HandlerFunc {
 
 			if err, ok := r.(error); ok {
 				httpStatus := httpStatus(err)
-				c.XML(httpStatus, errorResponse{StatusCode: httpStatus, Error: err.Error()})
+
+				if format := shared.ProblemFormat(c); format != "" {
+					problemType, title := problemTypeOf(err)
+					problem := shared.NewProblem(c, problemType, title, httpStatus, err)
+
+					if unavailable, ok := err.(unAvailableBookingError); ok && len(unavailable.alternatives) > 0 {
+						problem.Alternatives = unavailable.alternatives
+					}
+
+					shared.RenderProblem(c, format, problem)
+				} else {
+					response := errorResponse{StatusCode: httpStatus, Error: err.Error()}
+
+					if unavailable, ok := err.(unAvailableBookingError); ok {
+						response.Alternatives = unavailable.alternatives
+					}
+
+					render(c, httpStatus, response)
+				}
+
 				_ = c.Error(err)
 				c.Abort()
 			}
@@ -24,6 +47,50 @@ func errorHandlerMiddleware() gin.HandlerFunc {
 	}
 }
 
+// deprecationMiddleware announces deprecation of v1 routes by RFC 9745 Deprecation and RFC 8594 Sunset headers,
+// the matching v2 resource of the same branch is linked by Link header. Zero sunset is left out
+func deprecationMiddleware(deprecation, sunset time.Time) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.Contains(c.FullPath(), v1Path) {
+			path := c.Request.URL.Path
+			i := strings.Index(path, v1Path)
+			resource := path[i+len(v1Path):]
+
+			if successor, ok := v2Successors[resource]; ok {
+				resource = successor
+			}
+
+			c.Header("Deprecation", fmt.Sprintf("@%d", deprecation.Unix()))
+
+			if !sunset.IsZero() {
+				c.Header("Sunset", sunset.Format(http.TimeFormat))
+			}
+
+			c.Header("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, path[:i]+v2Path+resource))
+		}
+
+		c.Next()
+	}
+}
+
+const branchContextKey = "branch"
+
+// branchMiddleware resolves controller of the branch requested by branchId path parameter,
+// routes without the parameter are resolved by empty branch ID
+func branchMiddleware(branches map[string]*controller) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		branchID := c.Param("branchId")
+		branch, ok := branches[branchID]
+
+		if !ok {
+			panic(newUnknownBranchError(branchID))
+		}
+
+		c.Set(branchContextKey, branch)
+		c.Next()
+	}
+}
+
 func httpStatus(err error) (httpStatus int) {
 	switch err.(type) {
 
@@ -39,12 +106,108 @@ func httpStatus(err error) (httpStatus int) {
 
 		return
 
+	case unknownServiceTypeError:
+		httpStatus = http.StatusBadRequest
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case unknownVehicleTypeError:
+		httpStatus = http.StatusBadRequest
+		log.Infof("request encountered error: %s", err)
+
+		return
+
 	case unAvailableBookingError:
 		httpStatus = http.StatusUnprocessableEntity
 		log.Infof("request encountered error: %s", err)
 
 		return
 
+	case serviceDoesNotFitError:
+		httpStatus = http.StatusUnprocessableEntity
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case overlappingBookingError:
+		httpStatus = http.StatusUnprocessableEntity
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case bookingWindowError:
+		httpStatus = http.StatusUnprocessableEntity
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case bookingLimitExceededError:
+		httpStatus = http.StatusUnprocessableEntity
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case invalidBookingCancellationError:
+		httpStatus = http.StatusUnprocessableEntity
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case notBookedByContactError:
+		httpStatus = http.StatusUnprocessableEntity
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case invalidTireSetError:
+		httpStatus = http.StatusUnprocessableEntity
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case unsupportedVehicleTypeError:
+		httpStatus = http.StatusUnprocessableEntity
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case unknownWaitlistEntryError:
+		httpStatus = http.StatusNotFound
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case unknownTireChangeTimeError:
+		httpStatus = http.StatusNotFound
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case unknownTireSetError:
+		httpStatus = http.StatusNotFound
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case unknownMechanicError:
+		httpStatus = http.StatusNotFound
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case unknownMechanicAbsenceError:
+		httpStatus = http.StatusNotFound
+		log.Infof("request encountered error: %s", err)
+
+		return
+
+	case unknownBranchError:
+		httpStatus = http.StatusNotFound
+		log.Infof("request encountered error: %s", err)
+
+		return
+
 	default:
 		httpStatus = http.StatusInternalServerError
 		log.Errorf("request encountered error: %+v", err)
@@ -2,6 +2,7 @@ package london
 
 import (
 	"github.com/jinzhu/gorm"
+	"github.com/surmus/tire-change-workshop/internal/shared"
 	"time"
 )
 
@@ -13,26 +14,59 @@ func newTireChangeTimeRepository(db *gorm.DB) *tireChangeTimeRepository {
 	return &tireChangeTimeRepository{db: db}
 }
 
-func (r *tireChangeTimeRepository) availableByTimeRange(from time.Time, until time.Time) []*tireChangeTimeEntity {
+// availableByTimeRange returns page of available tire change times of the period with their total amount
+func (r *tireChangeTimeRepository) availableByTimeRange(
+	from time.Time,
+	until time.Time,
+	vehicleType string,
+	schedule *weeklySchedule,
+	offset uint,
+	limit uint,
+) ([]*tireChangeTimeEntity, uint) {
+	var total uint
 	results := make([]*tireChangeTimeEntity, 0)
 
 	query := r.db.Model(&tireChangeTimeEntity{}).
+		Scopes(supportingVehicleType(vehicleType), scheduledBy(schedule)).
 		Where("available = ?", true).
 		Where("time >= ?", from).
-		Where("time <= ?", until).
-		Order("time ASC")
+		Where("time <= ?", until)
 
-	if err := query.Find(&results).Error; err != nil {
+	if err := query.Count(&total).Error; err != nil {
 		panic(err)
 	}
 
-	return results
+	if limit > 0 {
+		query = query.Offset(offset).Limit(limit)
+	}
+
+	if err := query.Order("time ASC").Find(&results).Error; err != nil {
+		panic(err)
+	}
+
+	return results, total
+}
+
+func (r *tireChangeTimeRepository) countActiveByContact(contactInformation string, now time.Time) uint {
+	var count uint
+
+	query := r.db.Model(&tireChangeBookingEntity{}).
+		Joins("JOIN tire_change_time ON tire_change_time.id = tire_change_booking.tire_change_time_id").
+		Where("tire_change_booking.contact_information = ?", contactInformation).
+		Where("tire_change_booking.start_tire_change_time_id = tire_change_booking.tire_change_time_id").
+		Where("tire_change_time.time > ?", now)
+
+	if err := query.Count(&count).Error; err != nil {
+		panic(err)
+	}
+
+	return count
 }
 
 func (r *tireChangeTimeRepository) oneByUUID(uuid string) *tireChangeTimeEntity {
 	var result tireChangeTimeEntity
 
-	query := r.db.Model(&tireChangeTimeEntity{}).Where("uuid = ?", uuid)
+	query := r.db.Model(&tireChangeTimeEntity{}).Preload("Bookings").Where("uuid = ?", uuid)
 
 	if err := query.Find(&result).Error; gorm.IsRecordNotFoundError(err) {
 		return zeroTireChangeTimeEntity
@@ -43,6 +77,90 @@ func (r *tireChangeTimeRepository) oneByUUID(uuid string) *tireChangeTimeEntity
 	return &result
 }
 
+// consecutiveFrom returns start tire change time with directly following available ones, up to given total amount
+func (r *tireChangeTimeRepository) consecutiveFrom(start *tireChangeTimeEntity, amount int) []*tireChangeTimeEntity {
+	results := make([]*tireChangeTimeEntity, 0)
+	candidates := make([]*tireChangeTimeEntity, 0)
+
+	query := r.db.Model(&tireChangeTimeEntity{}).
+		Preload("Bookings").
+		Where("id = ? OR available = ?", start.ID, true).
+		Where("time >= ?", start.Time).
+		Where("time < ?", start.Time.Add(time.Duration(amount)*tireChangeTimeDuration)).
+		Order("time ASC")
+
+	if err := query.Find(&candidates).Error; err != nil {
+		panic(err)
+	}
+
+	for _, candidate := range candidates {
+		next := start.Time.Add(time.Duration(len(results)) * tireChangeTimeDuration)
+
+		if candidate.Time.After(next) {
+			break
+		} else if candidate.Time.Equal(next) {
+			results = append(results, candidate)
+		}
+	}
+
+	return results
+}
+
+// allByBooking returns all tire change times reserved together with given booking
+func (r *tireChangeTimeRepository) allByBooking(booking *tireChangeBookingEntity) []*tireChangeTimeEntity {
+	results := make([]*tireChangeTimeEntity, 0)
+
+	query := r.db.Model(&tireChangeTimeEntity{}).
+		Preload("Bookings").
+		Joins("JOIN tire_change_booking ON tire_change_booking.tire_change_time_id = tire_change_time.id").
+		Where("tire_change_booking.start_tire_change_time_id = ?", booking.StartTireChangeTimeID).
+		Where("tire_change_booking.contact_information = ?", booking.ContactInformation).
+		Order("time ASC")
+
+	if err := query.Find(&results).Error; err != nil {
+		panic(err)
+	}
+
+	return results
+}
+
+// allByTimeRange returns tire change times with their bookings from given time, zero until leaves the range open
+func (r *tireChangeTimeRepository) allByTimeRange(from time.Time, until time.Time) []*tireChangeTimeEntity {
+	results := make([]*tireChangeTimeEntity, 0)
+
+	query := r.db.Model(&tireChangeTimeEntity{}).
+		Preload("Bookings").
+		Where("time >= ?", from).
+		Order("time ASC")
+
+	if !until.IsZero() {
+		query = query.Where("time < ?", until)
+	}
+
+	if err := query.Find(&results).Error; err != nil {
+		panic(err)
+	}
+
+	return results
+}
+
+// allRequiringRescheduling returns tire change times from given time holding bookings flagged for rescheduling
+func (r *tireChangeTimeRepository) allRequiringRescheduling(from time.Time) []*tireChangeTimeEntity {
+	results := make([]*tireChangeTimeEntity, 0)
+
+	query := r.db.Model(&tireChangeTimeEntity{}).
+		Preload("Bookings").
+		Where("id IN (SELECT tire_change_time_id FROM tire_change_booking WHERE reschedule_required = ?)", true).
+		Where("time >= ?", from).
+		Order("time ASC")
+
+	if err := query.Find(&results).Error; err != nil {
+		panic(err)
+	}
+
+	return results
+}
+
 func (r *tireChangeTimeRepository) save(entity *tireChangeTimeEntity) *tireChangeTimeEntity {
 	if err := r.db.Save(entity).Error; err != nil {
 		panic(err)
@@ -50,3 +168,411 @@ func (r *tireChangeTimeRepository) save(entity *tireChangeTimeEntity) *tireChang
 
 	return entity
 }
+
+// transaction runs given function within database transaction, rolled back on error or panic
+func (r *tireChangeTimeRepository) transaction(fn func(repository *tireChangeTimeRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(newTireChangeTimeRepository(tx))
+	})
+}
+
+// reserve stores booking by conditional update of booked places, returns false when no place is left
+func (r *tireChangeTimeRepository) reserve(entity *tireChangeTimeEntity, booking *tireChangeBookingEntity) bool {
+	result := r.db.Model(&tireChangeTimeEntity{}).
+		Where("id = ? AND booked_count < capacity", entity.ID).
+		UpdateColumns(map[string]interface{}{
+			"booked_count": gorm.Expr("booked_count + 1"),
+			"available":    gorm.Expr("booked_count + 1 < capacity"),
+			"updated_at":   time.Now(),
+		})
+
+	if result.Error != nil {
+		panic(result.Error)
+	} else if result.RowsAffected == 0 {
+		return false
+	}
+
+	booking.TireChangeTimeID = entity.ID
+
+	if err := r.db.Create(booking).Error; err != nil {
+		panic(err)
+	}
+
+	return true
+}
+
+// release deletes booking of given tire change time and frees its place by conditional update of booked places
+func (r *tireChangeTimeRepository) release(entity *tireChangeTimeEntity, booking *tireChangeBookingEntity) {
+	err := r.db.Model(&tireChangeTimeEntity{}).
+		Where("id = ? AND booked_count > 0", entity.ID).
+		UpdateColumns(map[string]interface{}{
+			"booked_count": gorm.Expr("booked_count - 1"),
+			"available":    gorm.Expr("booked_count - 1 < capacity"),
+			"updated_at":   time.Now(),
+		}).Error
+
+	if err != nil {
+		panic(err)
+	}
+
+	r.deleteBooking(booking)
+}
+
+func (r *tireChangeTimeRepository) saveBooking(booking *tireChangeBookingEntity) *tireChangeBookingEntity {
+	if err := r.db.Save(booking).Error; err != nil {
+		panic(err)
+	}
+
+	return booking
+}
+
+func (r *tireChangeTimeRepository) deleteBooking(booking *tireChangeBookingEntity) {
+	if err := r.db.Delete(booking).Error; err != nil {
+		panic(err)
+	}
+}
+
+func (r *tireChangeTimeRepository) firstAvailableByDay(day time.Time) *tireChangeTimeEntity {
+	var result tireChangeTimeEntity
+
+	query := r.db.Model(&tireChangeTimeEntity{}).
+		Preload("Bookings").
+		Where("available = ?", true).
+		Where("time >= ?", day).
+		Where("time < ?", day.AddDate(0, 0, 1)).
+		Order("time ASC")
+
+	if err := query.First(&result).Error; gorm.IsRecordNotFoundError(err) {
+		return zeroTireChangeTimeEntity
+	} else if err != nil {
+		panic(err)
+	}
+
+	return &result
+}
+
+// dailyAvailability counts tire change times of the period by days of server time zone
+func (r *tireChangeTimeRepository) dailyAvailability(
+	from, until, now time.Time,
+	vehicleType string,
+) []*dailyAvailability {
+	results := make([]*dailyAvailability, 0)
+
+	query := r.db.Model(&tireChangeTimeEntity{}).
+		Select(
+			"date(time, 'localtime') AS date, COUNT(*) AS total, "+
+				"SUM(CASE WHEN available = ? AND time > ? THEN 1 ELSE 0 END) AS available, "+
+				"SUM(CASE WHEN booked_count > 0 AND booked_count >= capacity THEN 1 ELSE 0 END) AS booked",
+			true,
+			now,
+		).
+		Scopes(supportingVehicleType(vehicleType)).
+		Where("time >= ?", from).
+		Where("time < ?", until).
+		Group("date(time, 'localtime')").
+		Order("date ASC")
+
+	if err := query.Scan(&results).Error; err != nil {
+		panic(err)
+	}
+
+	return results
+}
+
+// nextAvailable returns given amount of earliest available tire change times after given time
+func (r *tireChangeTimeRepository) nextAvailable(after time.Time, vehicleType string, count uint) []*tireChangeTimeEntity {
+	results := make([]*tireChangeTimeEntity, 0)
+
+	query := r.db.Model(&tireChangeTimeEntity{}).
+		Scopes(supportingVehicleType(vehicleType)).
+		Where("available = ?", true).
+		Where("time > ?", after).
+		Order("time ASC").
+		Limit(count)
+
+	if err := query.Find(&results).Error; err != nil {
+		panic(err)
+	}
+
+	return results
+}
+
+// nearestAvailable returns upcoming available tire change times closest before and after given time
+func (r *tireChangeTimeRepository) nearestAvailable(
+	around time.Time,
+	now time.Time,
+	vehicleType string,
+	amount int,
+) (before []*tireChangeTimeEntity, after []*tireChangeTimeEntity) {
+	before = make([]*tireChangeTimeEntity, 0)
+	after = make([]*tireChangeTimeEntity, 0)
+
+	query := r.db.Model(&tireChangeTimeEntity{}).
+		Scopes(supportingVehicleType(vehicleType)).
+		Where("available = ?", true).
+		Limit(amount)
+
+	if err := query.Where("time < ?", around).Where("time > ?", now).Order("time DESC").Find(&before).Error; err != nil {
+		panic(err)
+	}
+
+	if err := query.Where("time > ?", around).Where("time > ?", now).Order("time ASC").Find(&after).Error; err != nil {
+		panic(err)
+	}
+
+	return before, after
+}
+
+// lastModified returns the latest change of tire change times, start of tire change time included
+func (r *tireChangeTimeRepository) lastModified(now time.Time) time.Time {
+	var updated, started tireChangeTimeEntity
+
+	if err := r.db.Model(&tireChangeTimeEntity{}).Order("updated_at DESC").First(&updated).Error; err != nil &&
+		!gorm.IsRecordNotFoundError(err) {
+		panic(err)
+	}
+
+	query := r.db.Model(&tireChangeTimeEntity{}).Where("time <= ?", now).Order("time DESC")
+
+	if err := query.First(&started).Error; err != nil && !gorm.IsRecordNotFoundError(err) {
+		panic(err)
+	}
+
+	if started.Time.After(updated.UpdatedAt) {
+		return started.Time
+	}
+
+	return updated.UpdatedAt
+}
+
+// scheduledBy limits tire change times to ones fitting given weekly schedule
+func scheduledBy(schedule *weeklySchedule) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if schedule.TimeFrom != "" {
+			db = db.Where("strftime('%H:%M', time, 'localtime') >= ?", schedule.TimeFrom)
+		}
+
+		if schedule.TimeUntil != "" {
+			db = db.Where("strftime('%H:%M', time, 'localtime') < ?", schedule.TimeUntil)
+		}
+
+		if len(schedule.Weekdays) > 0 {
+			db = db.Where("CAST(strftime('%w', time, 'localtime') AS INTEGER) IN (?)", schedule.weekdays())
+		}
+
+		return db
+	}
+}
+
+// supportingVehicleType limits tire change times to ones able to service given vehicle type, empty type allows all
+func supportingVehicleType(vehicleType string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if vehicleType == "" {
+			return db
+		}
+
+		return db.Where("vehicle_types = '' OR vehicle_types LIKE ?", "%,"+vehicleType+",%")
+	}
+}
+
+type serviceTypeRepository struct {
+	db *gorm.DB
+}
+
+func newServiceTypeRepository(db *gorm.DB) *serviceTypeRepository {
+	return &serviceTypeRepository{db: db}
+}
+
+func (r *serviceTypeRepository) all() []*serviceTypeEntity {
+	results := make([]*serviceTypeEntity, 0)
+
+	if err := r.db.Model(&serviceTypeEntity{}).Order("id ASC").Find(&results).Error; err != nil {
+		panic(err)
+	}
+
+	return results
+}
+
+func (r *serviceTypeRepository) oneByCode(code string) *serviceTypeEntity {
+	var result serviceTypeEntity
+
+	query := r.db.Model(&serviceTypeEntity{}).Where("code = ?", code)
+
+	if err := query.Find(&result).Error; gorm.IsRecordNotFoundError(err) {
+		return zeroServiceTypeEntity
+	} else if err != nil {
+		panic(err)
+	}
+
+	return &result
+}
+
+type waitlistRepository struct {
+	db *gorm.DB
+}
+
+func newWaitlistRepository(db *gorm.DB) *waitlistRepository {
+	return &waitlistRepository{db: db}
+}
+
+func (r *waitlistRepository) oneByUUID(uuid string) *waitlistEntryEntity {
+	var result waitlistEntryEntity
+
+	query := r.db.Model(&waitlistEntryEntity{}).Where("uuid = ?", uuid)
+
+	if err := query.Find(&result).Error; gorm.IsRecordNotFoundError(err) {
+		return zeroWaitlistEntryEntity
+	} else if err != nil {
+		panic(err)
+	}
+
+	return &result
+}
+
+// allWaitingFor returns entries registered either for given tire change time or for its day, longest waiting first
+func (r *waitlistRepository) allWaitingFor(tireChangeTime *tireChangeTimeEntity) []*waitlistEntryEntity {
+	results := make([]*waitlistEntryEntity, 0)
+
+	localTime := shared.InServerTimeZone(tireChangeTime.Time)
+	day := time.Date(localTime.Year(), localTime.Month(), localTime.Day(), 0, 0, 0, 0, time.Local)
+
+	query := r.db.Model(&waitlistEntryEntity{}).
+		Where("status = ?", waitlistStatusWaiting).
+		Where("tire_change_time_uuid = ? OR (tire_change_time_uuid = '' AND date = ?)", tireChangeTime.UUID, day).
+		Order("id ASC")
+
+	if err := query.Find(&results).Error; err != nil {
+		panic(err)
+	}
+
+	return results
+}
+
+func (r *waitlistRepository) save(entity *waitlistEntryEntity) *waitlistEntryEntity {
+	if err := r.db.Save(entity).Error; err != nil {
+		panic(err)
+	}
+
+	return entity
+}
+
+type tireSetRepository struct {
+	db *gorm.DB
+}
+
+func newTireSetRepository(db *gorm.DB) *tireSetRepository {
+	return &tireSetRepository{db: db}
+}
+
+func (r *tireSetRepository) oneByUUID(uuid string) *tireSetEntity {
+	var result tireSetEntity
+
+	query := r.db.Model(&tireSetEntity{}).Where("uuid = ?", uuid)
+
+	if err := query.Find(&result).Error; gorm.IsRecordNotFoundError(err) {
+		return zeroTireSetEntity
+	} else if err != nil {
+		panic(err)
+	}
+
+	return &result
+}
+
+func (r *tireSetRepository) allByContact(contactInformation string) []*tireSetEntity {
+	results := make([]*tireSetEntity, 0)
+
+	query := r.db.Model(&tireSetEntity{}).Where("contact_information = ?", contactInformation).Order("id ASC")
+
+	if err := query.Find(&results).Error; err != nil {
+		panic(err)
+	}
+
+	return results
+}
+
+func (r *tireSetRepository) save(entity *tireSetEntity) *tireSetEntity {
+	if err := r.db.Save(entity).Error; err != nil {
+		panic(err)
+	}
+
+	return entity
+}
+
+type mechanicRepository struct {
+	db *gorm.DB
+}
+
+func newMechanicRepository(db *gorm.DB) *mechanicRepository {
+	return &mechanicRepository{db: db}
+}
+
+func (r *mechanicRepository) all() []*mechanicEntity {
+	results := make([]*mechanicEntity, 0)
+
+	query := r.db.Model(&mechanicEntity{}).
+		Preload("Shifts", func(db *gorm.DB) *gorm.DB { return db.Order("weekday ASC, start_hour ASC") }).
+		Preload("Absences", func(db *gorm.DB) *gorm.DB { return db.Order("date ASC") }).
+		Order("id ASC")
+
+	if err := query.Find(&results).Error; err != nil {
+		panic(err)
+	}
+
+	return results
+}
+
+func (r *mechanicRepository) oneByUUID(uuid string) *mechanicEntity {
+	var result mechanicEntity
+
+	query := r.db.Model(&mechanicEntity{}).
+		Preload("Shifts", func(db *gorm.DB) *gorm.DB { return db.Order("weekday ASC, start_hour ASC") }).
+		Preload("Absences", func(db *gorm.DB) *gorm.DB { return db.Order("date ASC") }).
+		Where("uuid = ?", uuid)
+
+	if err := query.Find(&result).Error; gorm.IsRecordNotFoundError(err) {
+		return zeroMechanicEntity
+	} else if err != nil {
+		panic(err)
+	}
+
+	return &result
+}
+
+func (r *mechanicRepository) save(entity *mechanicEntity) *mechanicEntity {
+	if err := r.db.Save(entity).Error; err != nil {
+		panic(err)
+	}
+
+	return entity
+}
+
+// replaceShifts removes all weekly shifts of the mechanic and stores given ones instead
+func (r *mechanicRepository) replaceShifts(mechanic *mechanicEntity, shifts []*mechanicShiftEntity) *mechanicEntity {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("mechanic_id = ?", mechanic.ID).Delete(&mechanicShiftEntity{}).Error; err != nil {
+			return err
+		}
+
+		for _, shift := range shifts {
+			if err := tx.Create(shift).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		panic(err)
+	}
+
+	mechanic.Shifts = shifts
+
+	return mechanic
+}
+
+func (r *mechanicRepository) deleteAbsence(absence *mechanicAbsenceEntity) {
+	if err := r.db.Delete(absence).Error; err != nil {
+		panic(err)
+	}
+}
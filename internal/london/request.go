@@ -1,10 +1,148 @@
 package london
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// weeklySchedule limits tire change times to given weekdays and time of day in HH:MM format,
+// time of day is compared in server time zone and empty values allow all
+type weeklySchedule struct {
+	TimeFrom  string   `form:"timeFrom" binding:"omitempty,datetime=15:04"`
+	TimeUntil string   `form:"timeUntil" binding:"omitempty,datetime=15:04"`
+	Weekdays  []string `form:"weekdays" binding:"dive,oneof=MONDAY TUESDAY WEDNESDAY THURSDAY FRIDAY SATURDAY SUNDAY"`
+}
+
+// weekdays returns numbers of requested weekdays counted from Sunday as 0
+func (s *weeklySchedule) weekdays() []int {
+	var weekdays []int
+
+	for _, name := range s.Weekdays {
+		weekdays = append(weekdays, int(parseWeekday(name)))
+	}
+
+	return weekdays
+}
+
+// covers tells whether tire change time starting at given time fits the schedule
+func (s *weeklySchedule) covers(t time.Time) bool {
+	timeOfDay := t.Local().Format("15:04")
+
+	if s.TimeFrom != "" && timeOfDay < s.TimeFrom || s.TimeUntil != "" && timeOfDay >= s.TimeUntil {
+		return false
+	}
+
+	if len(s.Weekdays) == 0 {
+		return true
+	}
+
+	for _, weekday := range s.weekdays() {
+		if int(t.Local().Weekday()) == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseWeekday resolves weekday by its upper case English name, unknown names resolve to Sunday
+func parseWeekday(name string) time.Weekday {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.ToUpper(weekday.String()) == name {
+			return weekday
+		}
+	}
+
+	return time.Sunday
+}
 
 type tireChangeTimesSearchQuery struct {
-	From  time.Time `form:"from" time_format:"2006-01-02" binding:"required"`
-	Until time.Time `form:"until" time_format:"2006-01-02" binding:"required"`
+	weeklySchedule
+	From        time.Time `form:"from" time_format:"2006-01-02" binding:"required"`
+	Until       time.Time `form:"until" time_format:"2006-01-02" binding:"required"`
+	IncludePast bool      `form:"includePast"`
+	ServiceType string    `form:"serviceType"`
+	VehicleType string    `form:"vehicleType"`
+	Limit       uint      `form:"limit" binding:"required_with=Offset"`
+	Offset      uint      `form:"offset"`
+}
+
+func (q *tireChangeTimesSearchQuery) isPaginated() bool {
+	return q.Limit > 0
+}
+
+// paginate selects requested page from already fetched tire change times
+func (q *tireChangeTimesSearchQuery) paginate(entities []*tireChangeTimeEntity) []*tireChangeTimeEntity {
+	if !q.isPaginated() {
+		return entities
+	}
+
+	if q.Offset >= uint(len(entities)) {
+		return entities[:0]
+	}
+
+	end := q.Offset + q.Limit
+
+	if end > uint(len(entities)) {
+		end = uint(len(entities))
+	}
+
+	return entities[q.Offset:end]
+}
+
+// tireChangeTimesPageQuery selects page of available tire change times served by v2 API, every page is limited
+type tireChangeTimesPageQuery struct {
+	weeklySchedule
+	From        time.Time `form:"from" time_format:"2006-01-02" binding:"required"`
+	Until       time.Time `form:"until" time_format:"2006-01-02" binding:"required"`
+	IncludePast bool      `form:"includePast"`
+	ServiceType string    `form:"serviceType"`
+	VehicleType string    `form:"vehicleType"`
+	Limit       uint      `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset      uint      `form:"offset"`
+}
+
+// searchQuery converts the page query into paginated v1 search query
+func (q *tireChangeTimesPageQuery) searchQuery() *tireChangeTimesSearchQuery {
+	return &tireChangeTimesSearchQuery{
+		weeklySchedule: q.weeklySchedule,
+		From:           q.From,
+		Until:          q.Until,
+		IncludePast:    q.IncludePast,
+		ServiceType:    q.ServiceType,
+		VehicleType:    q.VehicleType,
+		Limit:          q.Limit,
+		Offset:         q.Offset,
+	}
+}
+
+// dailyAvailabilityQuery summarizes tire change times of days from date until date inclusive
+type dailyAvailabilityQuery struct {
+	From        time.Time `form:"from" time_format:"2006-01-02" binding:"required"`
+	Until       time.Time `form:"until" time_format:"2006-01-02" binding:"required"`
+	VehicleType string    `form:"vehicleType"`
+}
+
+// end returns the beginning of the day following until date
+func (q *dailyAvailabilityQuery) end() time.Time {
+	return q.Until.AddDate(0, 0, 1)
+}
+
+// nextAvailableQuery looks up given amount of earliest available tire change times starting after given time
+type nextAvailableQuery struct {
+	After       time.Time `form:"after" time_format:"2006-01-02T15:04:05Z07:00"`
+	Count       uint      `form:"count,default=1" binding:"min=1,max=50"`
+	VehicleType string    `form:"vehicleType"`
+}
+
+type tireChangeTimeURI struct {
+	UUID string `uri:"uuid" binding:"required,max=36,min=36"`
+}
+
+// tireChangeTimeQuery selects service and vehicle type to quote tire change time price for
+type tireChangeTimeQuery struct {
+	ServiceType string `form:"serviceType"`
+	VehicleType string `form:"vehicleType"`
 }
 
 type tireChangeBookingURI struct {
@@ -12,5 +150,92 @@ type tireChangeBookingURI struct {
 }
 
 type tireChangeBookingRequest struct {
-	ContactInformation string `xml:"contactInformation" binding:"required,min=1"`
+	ContactInformation string `xml:"contactInformation" json:"contactInformation" binding:"required,min=1"`
+	ServiceType        string `xml:"serviceType" json:"serviceType"`
+	VehicleType        string `xml:"vehicleType" json:"vehicleType"`
+	TireSetUUID        string `xml:"tireSetUuid" json:"tireSetUuid" binding:"omitempty,max=36,min=36"`
+}
+
+// tireChangeBookingTireSetRequest attaches tire set stored in tire hotel to already booked tire change time
+type tireChangeBookingTireSetRequest struct {
+	ContactInformation string `xml:"contactInformation" json:"contactInformation" binding:"required,min=1"`
+	TireSetUUID        string `xml:"tireSetUuid" json:"tireSetUuid" binding:"required,max=36,min=36"`
+}
+
+type tireChangeBookingCancellationRequest struct {
+	ContactInformation string `xml:"contactInformation" json:"contactInformation" binding:"required,min=1"`
+}
+
+type waitlistEntryURI struct {
+	UUID string `uri:"uuid" binding:"required,max=36,min=36"`
+}
+
+type waitlistRequest struct {
+	ContactInformation string `xml:"contactInformation" json:"contactInformation" binding:"required,min=1"`
+	TireChangeTimeUUID string `xml:"tireChangeTimeUuid" json:"tireChangeTimeUuid" binding:"required_without=Date,omitempty,max=36,min=36"`
+	Date               string `xml:"date" json:"date" binding:"required_without=TireChangeTimeUUID,omitempty,datetime=2006-01-02"`
+}
+
+func (r *waitlistRequest) day() time.Time {
+	if r.Date == "" {
+		return time.Time{}
+	}
+
+	day, _ := time.ParseInLocation("2006-01-02", r.Date, time.Local)
+
+	return day
+}
+
+type tireSetURI struct {
+	UUID string `uri:"uuid" binding:"required,max=36,min=36"`
+}
+
+type tireSetsSearchQuery struct {
+	ContactInformation string `form:"contactInformation" binding:"required,min=1"`
+}
+
+type tireSetRequest struct {
+	ContactInformation string `xml:"contactInformation" json:"contactInformation" binding:"required,min=1"`
+	Size               string `xml:"size" json:"size" binding:"required,min=1"`
+	Brand              string `xml:"brand" json:"brand" binding:"required,min=1"`
+	Condition          string `xml:"condition" json:"condition" binding:"required,oneof=NEW GOOD WORN"`
+	StorageLocation    string `xml:"storageLocation" json:"storageLocation" binding:"required,min=1"`
+}
+
+type mechanicURI struct {
+	UUID string `uri:"uuid" binding:"required,max=36,min=36"`
+}
+
+type mechanicAbsenceURI struct {
+	UUID        string `uri:"uuid" binding:"required,max=36,min=36"`
+	AbsenceUUID string `uri:"absenceUuid" binding:"required,max=36,min=36"`
+}
+
+type mechanicRequest struct {
+	Name string `xml:"name" json:"name" binding:"required,min=1"`
+}
+
+type mechanicShiftsRequest struct {
+	Shifts []*mechanicShiftRequest `xml:"shift" json:"shifts" binding:"dive"`
+}
+
+type mechanicShiftRequest struct {
+	Weekday   string `xml:"weekday" json:"weekday" binding:"required,oneof=MONDAY TUESDAY WEDNESDAY THURSDAY FRIDAY SATURDAY SUNDAY"`
+	StartHour uint   `xml:"startHour" json:"startHour" binding:"max=23"`
+	EndHour   uint   `xml:"endHour" json:"endHour" binding:"required,gtfield=StartHour,max=24"`
+}
+
+func (r *mechanicShiftRequest) weekday() time.Weekday {
+	return parseWeekday(r.Weekday)
+}
+
+type mechanicAbsenceRequest struct {
+	Date string `xml:"date" json:"date" binding:"required,datetime=2006-01-02"`
+	Type string `xml:"type" json:"type" binding:"required,oneof=DAY_OFF SICK_LEAVE"`
+}
+
+func (r *mechanicAbsenceRequest) day() time.Time {
+	day, _ := time.ParseInLocation("2006-01-02", r.Date, time.Local)
+
+	return day
 }
@@ -1,31 +1,354 @@
 package london
 
-import "time"
+import (
+	"github.com/surmus/tire-change-workshop/internal/shared"
+	"sort"
+	"strings"
+	"time"
+)
 
 type errorResponse struct {
-	StatusCode int    `xml:"statusCode"`
-	Error      string `xml:"error"`
+	StatusCode int    `xml:"statusCode" json:"statusCode"`
+	Error      string `xml:"error" json:"error"`
+	// Alternatives lists nearest available tire change times when requested one is unavailable
+	Alternatives []*tireChangeBookingResponse `xml:"alternatives>alternative,omitempty" json:"alternatives,omitempty"`
 }
 
 type tireChangeBookingResponse struct {
-	UUID string    `xml:"uuid"`
-	Time time.Time `xml:"time"`
+	UUID              string    `xml:"uuid" json:"uuid"`
+	Time              time.Time `xml:"time" json:"time"`
+	RemainingCapacity uint      `xml:"remainingCapacity" json:"remainingCapacity"`
+	Price             float64   `xml:"price,omitempty" json:"price,omitempty"`
+	TireSetUUID       string    `xml:"tireSetUuid,omitempty" json:"tireSetUuid,omitempty"`
+	VehicleTypes      []string  `xml:"vehicleTypes>vehicleType,omitempty" json:"vehicleTypes,omitempty"`
 }
 
-func newTireChangeTimeResponse(UUID string, time time.Time) *tireChangeBookingResponse {
-	return &tireChangeBookingResponse{UUID: UUID, Time: time.UTC()}
+func newTireChangeTimeResponse(entity *tireChangeTimeEntity, pricePennies int64) *tireChangeBookingResponse {
+	return &tireChangeBookingResponse{
+		UUID:              entity.UUID,
+		Time:              entity.Time.UTC(),
+		RemainingCapacity: entity.remainingCapacity(),
+		Price:             pounds(pricePennies),
+		VehicleTypes:      entity.supportedVehicleTypes(),
+	}
+}
+
+// pounds converts price in pennies to decimal pounds returned by the API
+func pounds(pennies int64) float64 {
+	return float64(pennies) / 100
 }
 
 type tireChangeTimesResponse struct {
-	AvailableTimes []*tireChangeBookingResponse `xml:"availableTime"`
+	AvailableTimes []*tireChangeBookingResponse `xml:"availableTime" json:"availableTimes"`
+	// Total is the amount of available times of the whole period, given only for paginated requests
+	Total *uint `xml:"total,omitempty" json:"total,omitempty"`
 }
 
-func newTireChangeTimesResponse(entities []*tireChangeTimeEntity) *tireChangeTimesResponse {
+func newTireChangeTimesResponse(
+	entities []*tireChangeTimeEntity,
+	priceOf func(*tireChangeTimeEntity) int64,
+) *tireChangeTimesResponse {
 	var availableTimes []*tireChangeBookingResponse
 
 	for _, entity := range entities {
-		availableTimes = append(availableTimes, newTireChangeTimeResponse(entity.UUID, entity.Time))
+		availableTimes = append(availableTimes, newTireChangeTimeResponse(entity, priceOf(entity)))
 	}
 
 	return &tireChangeTimesResponse{AvailableTimes: availableTimes}
 }
+
+// tireChangeTimesPageResponse is a page of available tire change times served by v2 API
+type tireChangeTimesPageResponse struct {
+	Items  []*tireChangeBookingResponse `json:"items"`
+	Total  uint                         `json:"total"`
+	Limit  uint                         `json:"limit"`
+	Offset uint                         `json:"offset"`
+}
+
+func newTireChangeTimesPageResponse(
+	query *tireChangeTimesPageQuery,
+	response *tireChangeTimesResponse,
+) *tireChangeTimesPageResponse {
+	page := &tireChangeTimesPageResponse{
+		Items:  make([]*tireChangeBookingResponse, 0, len(response.AvailableTimes)),
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+
+	page.Items = append(page.Items, response.AvailableTimes...)
+
+	if response.Total != nil {
+		page.Total = *response.Total
+	}
+
+	return page
+}
+
+// hasNext tells whether further tire change times follow the page
+func (r *tireChangeTimesPageResponse) hasNext() bool {
+	return r.Offset+uint(len(r.Items)) < r.Total
+}
+
+type dayAvailabilityResponse struct {
+	Date  string `xml:"date" json:"date"`
+	Total uint   `xml:"total" json:"total"`
+	// Available counts upcoming tire change times having free places left
+	Available uint `xml:"available" json:"available"`
+	// Booked counts tire change times having all places booked
+	Booked uint `xml:"booked" json:"booked"`
+}
+
+func newDayAvailabilityResponse(aggregate *dailyAvailability) *dayAvailabilityResponse {
+	return &dayAvailabilityResponse{
+		Date:      aggregate.Date,
+		Total:     aggregate.Total,
+		Available: aggregate.Available,
+		Booked:    aggregate.Booked,
+	}
+}
+
+type dailyAvailabilityResponse struct {
+	Days []*dayAvailabilityResponse `xml:"day" json:"days"`
+}
+
+func newDailyAvailabilityResponse(aggregates []*dailyAvailability) *dailyAvailabilityResponse {
+	days := make([]*dayAvailabilityResponse, 0, len(aggregates))
+
+	for _, aggregate := range aggregates {
+		days = append(days, newDayAvailabilityResponse(aggregate))
+	}
+
+	return &dailyAvailabilityResponse{Days: days}
+}
+
+type waitlistEntryResponse struct {
+	UUID               string                     `xml:"uuid" json:"uuid"`
+	Status             string                     `xml:"status" json:"status"`
+	TireChangeTimeUUID string                     `xml:"tireChangeTimeUuid,omitempty" json:"tireChangeTimeUuid,omitempty"`
+	Date               string                     `xml:"date,omitempty" json:"date,omitempty"`
+	AssignedTime       *tireChangeBookingResponse `xml:"assignedTime,omitempty" json:"assignedTime,omitempty"`
+}
+
+func newWaitlistEntryResponse(
+	entry *waitlistEntryEntity,
+	assignedTireChangeTime *tireChangeTimeEntity,
+) *waitlistEntryResponse {
+	response := &waitlistEntryResponse{
+		UUID:               entry.UUID,
+		Status:             entry.Status,
+		TireChangeTimeUUID: entry.TireChangeTimeUUID,
+	}
+
+	if !entry.Date.IsZero() {
+		response.Date = entry.Date.Format("2006-01-02")
+	}
+
+	if assignedTireChangeTime != nil {
+		response.AssignedTime = newTireChangeTimeResponse(assignedTireChangeTime, 0)
+
+		if booking := assignedTireChangeTime.bookingOf(entry.ContactInformation); booking != nil {
+			response.AssignedTime.Price = pounds(booking.PricePennies)
+		}
+	}
+
+	return response
+}
+
+type serviceTypeResponse struct {
+	Code            string  `xml:"code" json:"code"`
+	Name            string  `xml:"name" json:"name"`
+	DurationMinutes uint    `xml:"durationMinutes" json:"durationMinutes"`
+	Price           float64 `xml:"price" json:"price"`
+}
+
+type serviceTypesResponse struct {
+	ServiceTypes []*serviceTypeResponse `xml:"serviceType" json:"serviceTypes"`
+}
+
+func newServiceTypesResponse(entities []*serviceTypeEntity) *serviceTypesResponse {
+	var serviceTypes []*serviceTypeResponse
+
+	for _, entity := range entities {
+		serviceTypes = append(serviceTypes, &serviceTypeResponse{
+			Code:            entity.Code,
+			Name:            entity.Name,
+			DurationMinutes: entity.DurationMinutes,
+			Price:           pounds(entity.PricePennies),
+		})
+	}
+
+	return &serviceTypesResponse{ServiceTypes: serviceTypes}
+}
+
+type tireSetResponse struct {
+	UUID               string `xml:"uuid" json:"uuid"`
+	ContactInformation string `xml:"contactInformation" json:"contactInformation"`
+	Size               string `xml:"size" json:"size"`
+	Brand              string `xml:"brand" json:"brand"`
+	Condition          string `xml:"condition" json:"condition"`
+	StorageLocation    string `xml:"storageLocation" json:"storageLocation"`
+}
+
+func newTireSetResponse(entity *tireSetEntity) *tireSetResponse {
+	return &tireSetResponse{
+		UUID:               entity.UUID,
+		ContactInformation: entity.ContactInformation,
+		Size:               entity.Size,
+		Brand:              entity.Brand,
+		Condition:          entity.Condition,
+		StorageLocation:    entity.StorageLocation,
+	}
+}
+
+type tireSetsResponse struct {
+	TireSets []*tireSetResponse `xml:"tireSet" json:"tireSets"`
+}
+
+func newTireSetsResponse(entities []*tireSetEntity) *tireSetsResponse {
+	var tireSets []*tireSetResponse
+
+	for _, entity := range entities {
+		tireSets = append(tireSets, newTireSetResponse(entity))
+	}
+
+	return &tireSetsResponse{TireSets: tireSets}
+}
+
+type workshopResponse struct {
+	Name         string   `xml:"name" json:"name"`
+	Address      string   `xml:"address" json:"address"`
+	Latitude     float64  `xml:"coordinates>latitude" json:"latitude"`
+	Longitude    float64  `xml:"coordinates>longitude" json:"longitude"`
+	OpeningHours string   `xml:"openingHours" json:"openingHours"`
+	TimeZone     string   `xml:"timeZone" json:"timeZone"`
+	VehicleTypes []string `xml:"vehicleTypes>vehicleType" json:"vehicleTypes"`
+}
+
+func newWorkshopResponse(info WorkshopInfo, vehicleTypes []string) *workshopResponse {
+	return &workshopResponse{
+		Name:         info.Name,
+		Address:      info.Address,
+		Latitude:     info.Latitude,
+		Longitude:    info.Longitude,
+		OpeningHours: info.OpeningHours,
+		TimeZone:     shared.ServerTimeZone(),
+		VehicleTypes: vehicleTypes,
+	}
+}
+
+type branchResponse struct {
+	ID       string            `xml:"id" json:"id"`
+	Workshop *workshopResponse `xml:"workshop" json:"workshop"`
+}
+
+type branchesResponse struct {
+	Branches []*branchResponse `xml:"branch" json:"branches"`
+}
+
+// newBranchesResponse lists branches with their workshop details ordered by branch ID
+func newBranchesResponse(workshops map[string]*workshopResponse) *branchesResponse {
+	var branches []*branchResponse
+
+	for id, workshop := range workshops {
+		branches = append(branches, &branchResponse{ID: id, Workshop: workshop})
+	}
+
+	sort.Slice(branches, func(i, j int) bool { return branches[i].ID < branches[j].ID })
+
+	return &branchesResponse{Branches: branches}
+}
+
+type mechanicShiftResponse struct {
+	Weekday   string `xml:"weekday" json:"weekday"`
+	StartHour uint   `xml:"startHour" json:"startHour"`
+	EndHour   uint   `xml:"endHour" json:"endHour"`
+}
+
+type mechanicAbsenceResponse struct {
+	UUID                string                        `xml:"uuid" json:"uuid"`
+	Date                string                        `xml:"date" json:"date"`
+	Type                string                        `xml:"type" json:"type"`
+	RescheduledBookings []*rescheduledBookingResponse `xml:"rescheduledBookings>booking,omitempty" json:"rescheduledBookings,omitempty"`
+}
+
+func newMechanicAbsenceResponse(entity *mechanicAbsenceEntity) *mechanicAbsenceResponse {
+	return &mechanicAbsenceResponse{
+		UUID: entity.UUID,
+		Date: entity.Date.Format("2006-01-02"),
+		Type: entity.Type,
+	}
+}
+
+type mechanicResponse struct {
+	UUID     string                     `xml:"uuid" json:"uuid"`
+	Name     string                     `xml:"name" json:"name"`
+	Shifts   []*mechanicShiftResponse   `xml:"shifts>shift" json:"shifts"`
+	Absences []*mechanicAbsenceResponse `xml:"absences>absence" json:"absences"`
+}
+
+func newMechanicResponse(entity *mechanicEntity) *mechanicResponse {
+	response := &mechanicResponse{UUID: entity.UUID, Name: entity.Name}
+
+	for _, shift := range entity.Shifts {
+		response.Shifts = append(response.Shifts, &mechanicShiftResponse{
+			Weekday:   strings.ToUpper(shift.Weekday.String()),
+			StartHour: shift.StartHour,
+			EndHour:   shift.EndHour,
+		})
+	}
+
+	for _, absence := range entity.Absences {
+		response.Absences = append(response.Absences, newMechanicAbsenceResponse(absence))
+	}
+
+	return response
+}
+
+type mechanicsResponse struct {
+	Mechanics []*mechanicResponse `xml:"mechanic" json:"mechanics"`
+}
+
+func newMechanicsResponse(entities []*mechanicEntity) *mechanicsResponse {
+	var mechanics []*mechanicResponse
+
+	for _, entity := range entities {
+		mechanics = append(mechanics, newMechanicResponse(entity))
+	}
+
+	return &mechanicsResponse{Mechanics: mechanics}
+}
+
+type rescheduledBookingResponse struct {
+	TireChangeTimeUUID string    `xml:"tireChangeTimeUuid" json:"tireChangeTimeUuid"`
+	Time               time.Time `xml:"time" json:"time"`
+	ContactInformation string    `xml:"contactInformation" json:"contactInformation"`
+	ServiceType        string    `xml:"serviceType" json:"serviceType"`
+	VehicleType        string    `xml:"vehicleType" json:"vehicleType"`
+}
+
+type rescheduledBookingsResponse struct {
+	Bookings []*rescheduledBookingResponse `xml:"booking" json:"bookings"`
+}
+
+// newRescheduledBookingResponses lists bookings flagged for rescheduling held in given tire change times
+func newRescheduledBookingResponses(entities []*tireChangeTimeEntity) []*rescheduledBookingResponse {
+	var bookings []*rescheduledBookingResponse
+
+	for _, entity := range entities {
+		for _, booking := range entity.Bookings {
+			if !booking.RescheduleRequired {
+				continue
+			}
+
+			bookings = append(bookings, &rescheduledBookingResponse{
+				TireChangeTimeUUID: entity.UUID,
+				Time:               entity.Time.UTC(),
+				ContactInformation: booking.ContactInformation,
+				ServiceType:        booking.ServiceTypeCode,
+				VehicleType:        booking.VehicleType,
+			})
+		}
+	}
+
+	return bookings
+}
@@ -2,41 +2,957 @@ package london
 
 import (
 	log "github.com/sirupsen/logrus"
+	"github.com/surmus/tire-change-workshop/internal/shared"
+	"sort"
 	"time"
 )
 
 type tireChangeTimesService struct {
-	repository *tireChangeTimeRepository
+	repository   *tireChangeTimeRepository
+	serviceTypes *serviceTypeRepository
+	tireSets     *tireSetRepository
+	waitlist     *waitlistService
+	rules        *bookingRules
+	pricing      *pricingEngine
 }
 
-func newTireChangeTimesService(repository *tireChangeTimeRepository) *tireChangeTimesService {
-	return &tireChangeTimesService{repository: repository}
+func newTireChangeTimesService(
+	repository *tireChangeTimeRepository,
+	serviceTypes *serviceTypeRepository,
+	tireSets *tireSetRepository,
+	waitlist *waitlistService,
+	rules *bookingRules,
+	pricing *pricingEngine,
+) *tireChangeTimesService {
+	return &tireChangeTimesService{
+		repository:   repository,
+		serviceTypes: serviceTypes,
+		tireSets:     tireSets,
+		waitlist:     waitlist,
+		rules:        rules,
+		pricing:      pricing,
+	}
 }
 
-func (s *tireChangeTimesService) getAvailable(from time.Time, until time.Time) (*tireChangeTimesResponse, error) {
+func (s *tireChangeTimesService) getAvailable(query *tireChangeTimesSearchQuery) (*tireChangeTimesResponse, error) {
+	from, until := query.From, query.Until
 	log.Infof("fetching tire change times from %s until %s", from, until)
 
 	if !from.Equal(until) && until.Before(from) {
 		return nil, newInvalidTirChangeTimesPeriodError(from, until)
 	}
 
-	tireChangeTimes := s.repository.availableByTimeRange(from, until)
+	serviceType, err := s.serviceType(query.ServiceType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	vehicleType, err := s.vehicleType(query.VehicleType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if now := time.Now(); !query.IncludePast && from.Before(now) {
+		from = now
+	}
+
+	var tireChangeTimes []*tireChangeTimeEntity
+	var total uint
+
+	if serviceType.requiredTireChangeTimes() == 1 {
+		tireChangeTimes, total = s.repository.availableByTimeRange(
+			from,
+			until,
+			query.VehicleType,
+			&query.weeklySchedule,
+			query.Offset,
+			query.Limit,
+		)
+	} else {
+		tireChangeTimes = s.fittingStartTimes(from, until, query.VehicleType, &query.weeklySchedule, serviceType)
+		total = uint(len(tireChangeTimes))
+		tireChangeTimes = query.paginate(tireChangeTimes)
+	}
 
 	log.Infof("successfully fetched %d tire change times from %s until %s", len(tireChangeTimes), from, until)
 
-	return newTireChangeTimesResponse(tireChangeTimes), nil
+	response := newTireChangeTimesResponse(tireChangeTimes, func(tireChangeTime *tireChangeTimeEntity) int64 {
+		return s.pricing.quote(serviceType, vehicleType, tireChangeTime.Time)
+	})
+
+	if query.isPaginated() {
+		response.Total = &total
+	}
+
+	return response, nil
+}
+
+// getPage returns requested page of available tire change times with the total amount of available times
+func (s *tireChangeTimesService) getPage(query *tireChangeTimesPageQuery) (*tireChangeTimesPageResponse, error) {
+	response, err := s.getAvailable(query.searchQuery())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newTireChangeTimesPageResponse(query, response), nil
 }
 
-func (s *tireChangeTimesService) book(uuid string, contactInformation string) (*tireChangeBookingResponse, error) {
-	log.Infof("trying to book tire change time with uuid: %s", uuid)
+// getByUUID returns tire change time with its price quoted for the service and vehicle type of given query
+func (s *tireChangeTimesService) getByUUID(
+	uuid string,
+	query *tireChangeTimeQuery,
+) (*tireChangeBookingResponse, error) {
 	tireChangeTime := s.repository.oneByUUID(uuid)
 
-	if bookingErr := tireChangeTime.makeBooking(contactInformation); bookingErr != nil {
-		return nil, bookingErr
+	if tireChangeTime == zeroTireChangeTimeEntity {
+		return nil, newUnknownTireChangeTimeError(uuid)
+	}
+
+	serviceType, err := s.serviceType(query.ServiceType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	vehicleType, err := s.vehicleType(query.VehicleType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newTireChangeTimeResponse(tireChangeTime, s.pricing.quote(serviceType, vehicleType, tireChangeTime.Time)), nil
+}
+
+// fittingStartTimes returns available start times of the period followed by enough available times for the service
+func (s *tireChangeTimesService) fittingStartTimes(
+	from time.Time,
+	until time.Time,
+	vehicleType string,
+	schedule *weeklySchedule,
+	serviceType *serviceTypeEntity,
+) []*tireChangeTimeEntity {
+	var startTimes []*tireChangeTimeEntity
+
+	// service started at the end of the period may last beyond it
+	available, _ := s.repository.availableByTimeRange(
+		from,
+		until.Add(serviceType.duration()),
+		vehicleType,
+		&weeklySchedule{},
+		0,
+		0,
+	)
+
+	for _, tireChangeTime := range fittingStartTimes(available, serviceType) {
+		if !tireChangeTime.Time.After(until) && schedule.covers(tireChangeTime.Time) {
+			startTimes = append(startTimes, tireChangeTime)
+		}
+	}
+
+	return startTimes
+}
+
+// getDailyAvailability counts tire change times of the days of given query able to service its vehicle type
+func (s *tireChangeTimesService) getDailyAvailability(query *dailyAvailabilityQuery) (*dailyAvailabilityResponse, error) {
+	if until := query.Until; until.Before(query.From) {
+		return nil, newInvalidTirChangeTimesPeriodError(query.From, until)
 	}
 
-	tireChangeTime = s.repository.save(tireChangeTime)
+	if _, err := s.vehicleType(query.VehicleType); err != nil {
+		return nil, err
+	}
+
+	days := s.repository.dailyAvailability(query.From, query.end(), time.Now(), query.VehicleType)
+	log.Infof("successfully summarized tire change times of %d days from %s until %s", len(days), query.From, query.Until)
+
+	return newDailyAvailabilityResponse(days), nil
+}
+
+// getNextAvailable returns earliest upcoming available tire change times after the time of given query
+func (s *tireChangeTimesService) getNextAvailable(query *nextAvailableQuery) (*tireChangeTimesResponse, error) {
+	serviceType, err := s.serviceType("")
+
+	if err != nil {
+		return nil, err
+	}
+
+	vehicleType, err := s.vehicleType(query.VehicleType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	after := shared.InServerTimeZone(query.After)
+
+	if now := time.Now(); after.Before(now) {
+		after = now
+	}
+
+	tireChangeTimes := s.repository.nextAvailable(after, query.VehicleType, query.Count)
+	log.Infof("successfully fetched %d next available tire change times after %s", len(tireChangeTimes), after)
+
+	return newTireChangeTimesResponse(tireChangeTimes, func(tireChangeTime *tireChangeTimeEntity) int64 {
+		return s.pricing.quote(serviceType, vehicleType, tireChangeTime.Time)
+	}), nil
+}
+
+// alternativesPerSide is the amount of alternatives suggested before as well as after unavailable tire change time
+const alternativesPerSide = 2
+
+// alternativesTo returns nearest start times around given tire change time bookable by the contact instead
+func (s *tireChangeTimesService) alternativesTo(
+	tireChangeTime *tireChangeTimeEntity,
+	serviceType *serviceTypeEntity,
+	vehicleType string,
+	contactInformation string,
+) []*tireChangeBookingResponse {
+	alternatives := make([]*tireChangeBookingResponse, 0)
+
+	if tireChangeTime == zeroTireChangeTimeEntity {
+		return alternatives
+	}
+
+	required := serviceType.requiredTireChangeTimes()
+	before, after := s.repository.nearestAvailable(
+		tireChangeTime.Time,
+		time.Now(),
+		vehicleType,
+		alternativesPerSide*required,
+	)
+
+	fitting := func(candidates []*tireChangeTimeEntity) []*tireChangeTimeEntity {
+		var results []*tireChangeTimeEntity
+
+		for _, candidate := range candidates {
+			if len(results) == alternativesPerSide {
+				break
+			}
+
+			if !s.fits(candidate, required, vehicleType) {
+				continue
+			}
+
+			if s.rules.check(s.repository, candidate, contactInformation) == nil {
+				results = append(results, candidate)
+			}
+		}
+
+		return results
+	}
+
+	before = fitting(before)
+
+	for i := len(before) - 1; i >= 0; i-- {
+		alternatives = append(alternatives, newTireChangeTimeResponse(
+			before[i],
+			s.pricing.quote(serviceType, vehicleType, before[i].Time),
+		))
+	}
+
+	for _, alternative := range fitting(after) {
+		alternatives = append(alternatives, newTireChangeTimeResponse(
+			alternative,
+			s.pricing.quote(serviceType, vehicleType, alternative.Time),
+		))
+	}
+
+	return alternatives
+}
+
+// fits tells whether tire change times from given start fit the service and vehicle type
+func (s *tireChangeTimesService) fits(start *tireChangeTimeEntity, required int, vehicleType string) bool {
+	if required == 1 {
+		return true
+	}
+
+	tireChangeTimes := s.repository.consecutiveFrom(start, required)
+
+	for _, tireChangeTime := range tireChangeTimes {
+		if !tireChangeTime.supports(vehicleType) {
+			return false
+		}
+	}
+
+	return len(tireChangeTimes) == required
+}
+
+// lastModified returns the latest change of tire change times affecting their search results
+func (s *tireChangeTimesService) lastModified() time.Time {
+	return s.repository.lastModified(time.Now())
+}
+
+func (s *tireChangeTimesService) getServiceTypes() *serviceTypesResponse {
+	return newServiceTypesResponse(s.serviceTypes.all())
+}
+
+func (s *tireChangeTimesService) book(uuid string, request *tireChangeBookingRequest) (*tireChangeBookingResponse, error) {
+	log.Infof("trying to book tire change time with uuid: %s for service: %s", uuid, request.ServiceType)
+	serviceType, err := s.serviceType(request.ServiceType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	vehicleType, err := s.vehicleType(request.VehicleType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tireSet := zeroTireSetEntity
+
+	if request.TireSetUUID != "" {
+		tireSet = s.tireSets.oneByUUID(request.TireSetUUID)
+
+		if tireSet == zeroTireSetEntity || tireSet.ContactInformation != request.ContactInformation {
+			return nil, newInvalidTireSetError(request.TireSetUUID)
+		}
+	}
+
+	var tireChangeTime *tireChangeTimeEntity
+	var tireChangeTimes []*tireChangeTimeEntity
+
+	// tire change times are loaded, checked and reserved within single transaction, so concurrent bookings can
+	// neither take the same places nor bypass booking rules
+	err = s.repository.transaction(func(repository *tireChangeTimeRepository) error {
+		tireChangeTime = repository.oneByUUID(uuid)
+
+		if rulesErr := s.rules.check(repository, tireChangeTime, request.ContactInformation); rulesErr != nil {
+			return rulesErr
+		}
+
+		tireChangeTimes = []*tireChangeTimeEntity{tireChangeTime}
+
+		if required := serviceType.requiredTireChangeTimes(); required > 1 && tireChangeTime != zeroTireChangeTimeEntity {
+			if tireChangeTimes = repository.consecutiveFrom(tireChangeTime, required); len(tireChangeTimes) < required {
+				return newServiceDoesNotFitError(tireChangeTime, serviceType)
+			}
+		}
+
+		for _, reserved := range tireChangeTimes {
+			if !reserved.supports(vehicleType) {
+				return newUnsupportedVehicleTypeError(reserved, vehicleType)
+			}
+		}
+
+		if overlapping := overlappingBooking(tireChangeTimes, request.ContactInformation); overlapping != nil {
+			return newOverlappingBookingError(overlapping)
+		}
+
+		price := s.pricing.quote(serviceType, vehicleType, tireChangeTime.Time)
+
+		for _, reserved := range tireChangeTimes {
+			if reserved.bookingOf(request.ContactInformation) != nil {
+				continue
+			}
+
+			booking := newTireChangeBookingEntity(
+				tireChangeTimes[0],
+				request.ContactInformation,
+				serviceType.Code,
+				vehicleType,
+				price,
+			)
+
+			if tireSet != zeroTireSetEntity {
+				booking.attachTireSet(tireSet)
+			}
+
+			if bookingErr := reserved.makeBooking(booking); bookingErr != nil {
+				return bookingErr
+			}
+
+			if !repository.reserve(reserved, booking) {
+				return newUnAvailableBookingError(reserved)
+			}
+		}
+
+		return nil
+	})
+
+	if unavailable, ok := err.(unAvailableBookingError); ok {
+		return nil, unavailable.withAlternatives(s.alternativesTo(tireChangeTime, serviceType, vehicleType, request.ContactInformation))
+	} else if err != nil {
+		return nil, err
+	}
 
 	log.Infof("successfully booked tire change time with uuid: %s", uuid)
-	return newTireChangeTimeResponse(tireChangeTime.UUID, tireChangeTime.Time), nil
+	booking := tireChangeTimes[0].bookingOf(request.ContactInformation)
+	response := newTireChangeTimeResponse(tireChangeTimes[0], booking.PricePennies)
+	response.TireSetUUID = booking.TireSetUUID
+
+	return response, nil
+}
+
+// attachTireSet attaches tire set of the contact to all tire change times booked together with given one
+func (s *tireChangeTimesService) attachTireSet(
+	uuid string,
+	request *tireChangeBookingTireSetRequest,
+) (*tireChangeBookingResponse, error) {
+	log.Infof("trying to attach tire set %s to tire change time booking with uuid: %s", request.TireSetUUID, uuid)
+	tireSet := s.tireSets.oneByUUID(request.TireSetUUID)
+
+	if tireSet == zeroTireSetEntity || tireSet.ContactInformation != request.ContactInformation {
+		return nil, newInvalidTireSetError(request.TireSetUUID)
+	}
+
+	var tireChangeTime *tireChangeTimeEntity
+	var booking *tireChangeBookingEntity
+
+	err := s.repository.transaction(func(repository *tireChangeTimeRepository) error {
+		tireChangeTime = repository.oneByUUID(uuid)
+
+		if booking = tireChangeTime.bookingOf(request.ContactInformation); booking == nil {
+			return newNotBookedByContactError(tireChangeTime)
+		}
+
+		for _, reserved := range repository.allByBooking(booking) {
+			reservedBooking := reserved.bookingOf(request.ContactInformation)
+			reservedBooking.attachTireSet(tireSet)
+			repository.saveBooking(reservedBooking)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("successfully attached tire set %s to tire change time booking with uuid: %s", tireSet.UUID, uuid)
+	response := newTireChangeTimeResponse(tireChangeTime, booking.PricePennies)
+	response.TireSetUUID = tireSet.UUID
+
+	return response, nil
+}
+
+// overlappingBooking returns the first of tire change times booked by the contact for another service
+func overlappingBooking(tireChangeTimes []*tireChangeTimeEntity, contactInformation string) *tireChangeTimeEntity {
+	var overlapping *tireChangeTimeEntity
+	held := 0
+
+	for _, reserved := range tireChangeTimes {
+		if booking := reserved.bookingOf(contactInformation); booking == nil {
+			continue
+		} else if booking.StartTireChangeTimeID != tireChangeTimes[0].ID {
+			return reserved
+		} else if overlapping == nil {
+			overlapping = reserved
+		}
+
+		held++
+	}
+
+	if held < len(tireChangeTimes) {
+		return overlapping
+	}
+
+	return nil
+}
+
+// cancelBooking releases all tire change times reserved by the contact together with given tire change time
+func (s *tireChangeTimesService) cancelBooking(uuid string, contactInformation string) (*tireChangeBookingResponse, error) {
+	log.Infof("trying to cancel tire change time booking with uuid: %s", uuid)
+	tireChangeTime := s.repository.oneByUUID(uuid)
+	booking := tireChangeTime.bookingOf(contactInformation)
+
+	if booking == nil {
+		return nil, newInvalidBookingCancellationError(tireChangeTime)
+	}
+
+	var released []*tireChangeTimeEntity
+
+	err := s.repository.transaction(func(repository *tireChangeTimeRepository) error {
+		for _, reserved := range repository.allByBooking(booking) {
+			releasedBooking, cancellationErr := reserved.cancelBooking(contactInformation)
+
+			if cancellationErr != nil {
+				return cancellationErr
+			}
+
+			repository.release(reserved, releasedBooking)
+			released = append(released, reserved)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// released places are offered to the waitlist only once the cancellation is committed
+	for _, reserved := range released {
+		s.waitlist.offer(reserved)
+
+		if reserved.ID == tireChangeTime.ID {
+			tireChangeTime = reserved
+		}
+	}
+
+	log.Infof("successfully cancelled tire change time booking with uuid: %s", uuid)
+
+	return newTireChangeTimeResponse(tireChangeTime, 0), nil
+}
+
+// serviceType resolves service type by code, empty code refers to default tire change service
+func (s *tireChangeTimesService) serviceType(code string) (*serviceTypeEntity, error) {
+	if code == "" {
+		code = defaultServiceTypeCode
+	}
+
+	if serviceType := s.serviceTypes.oneByCode(code); serviceType != zeroServiceTypeEntity {
+		return serviceType, nil
+	}
+
+	return nil, newUnknownServiceTypeError(code)
+}
+
+// vehicleType verifies vehicle type to be priced by the workshop, empty vehicle type refers to passenger car
+func (s *tireChangeTimesService) vehicleType(vehicleType string) (string, error) {
+	if vehicleType == "" {
+		return defaultVehicleType, nil
+	}
+
+	if !s.pricing.supports(vehicleType) {
+		return "", newUnknownVehicleTypeError(vehicleType)
+	}
+
+	return vehicleType, nil
+}
+
+type waitlistService struct {
+	repository               *waitlistRepository
+	tireChangeTimeRepository *tireChangeTimeRepository
+	serviceTypes             *serviceTypeRepository
+	rules                    *bookingRules
+	pricing                  *pricingEngine
+}
+
+func newWaitlistService(
+	repository *waitlistRepository,
+	tireChangeTimeRepository *tireChangeTimeRepository,
+	serviceTypes *serviceTypeRepository,
+	rules *bookingRules,
+	pricing *pricingEngine,
+) *waitlistService {
+	return &waitlistService{
+		repository:               repository,
+		tireChangeTimeRepository: tireChangeTimeRepository,
+		serviceTypes:             serviceTypes,
+		rules:                    rules,
+		pricing:                  pricing,
+	}
+}
+
+func (s *waitlistService) register(request *waitlistRequest) (*waitlistEntryResponse, error) {
+	log.Infof("registering waitlist entry for request: %+v", request)
+	entry := newWaitlistEntryEntity(request.ContactInformation, request.TireChangeTimeUUID, request.day())
+
+	var tireChangeTime *tireChangeTimeEntity
+
+	if entry.TireChangeTimeUUID != "" {
+		if tireChangeTime = s.tireChangeTimeRepository.oneByUUID(entry.TireChangeTimeUUID); tireChangeTime == zeroTireChangeTimeEntity {
+			return nil, newUnAvailableBookingError(tireChangeTime)
+		}
+	} else {
+		tireChangeTime = s.tireChangeTimeRepository.firstAvailableByDay(entry.Date)
+	}
+
+	// interest in currently free time is fulfilled right away
+	if tireChangeTime.Available && s.reserve(tireChangeTime, entry) == nil {
+		entry.assign(tireChangeTime)
+	}
+
+	entry = s.repository.save(entry)
+	log.Infof("successfully registered waitlist entry %s with status %s", entry.UUID, entry.Status)
+
+	return s.newWaitlistEntryResponse(entry), nil
+}
+
+func (s *waitlistService) get(uuid string) (*waitlistEntryResponse, error) {
+	entry := s.repository.oneByUUID(uuid)
+
+	if entry == zeroWaitlistEntryEntity {
+		return nil, newUnknownWaitlistEntryError(uuid)
+	}
+
+	return s.newWaitlistEntryResponse(entry), nil
+}
+
+// offer assigns released tire change time to the longest waiting matching waitlist entry allowed to book it
+func (s *waitlistService) offer(tireChangeTime *tireChangeTimeEntity) {
+	for _, entry := range s.repository.allWaitingFor(tireChangeTime) {
+		err := s.reserve(tireChangeTime, entry)
+
+		if _, unavailable := err.(unAvailableBookingError); unavailable {
+			log.Infof("could not assign tire change time %s to waitlist entry %s: %s", tireChangeTime.UUID, entry.UUID, err)
+			return
+		} else if err != nil {
+			log.Infof("skipping waitlist entry %s for tire change time %s: %s", entry.UUID, tireChangeTime.UUID, err)
+			continue
+		}
+
+		entry.assign(tireChangeTime)
+		s.repository.save(entry)
+
+		log.Infof("assigned tire change time %s to waitlist entry %s", tireChangeTime.UUID, entry.UUID)
+
+		return
+	}
+}
+
+// reserve books tire change time for waitlisted contact within transaction checking booking rules
+func (s *waitlistService) reserve(tireChangeTime *tireChangeTimeEntity, entry *waitlistEntryEntity) error {
+	booking := s.newBooking(tireChangeTime, entry)
+
+	return s.tireChangeTimeRepository.transaction(func(repository *tireChangeTimeRepository) error {
+		if err := s.rules.check(repository, tireChangeTime, entry.ContactInformation); err != nil {
+			return err
+		}
+
+		if tireChangeTime.bookingOf(entry.ContactInformation) != nil {
+			return nil
+		}
+
+		if err := tireChangeTime.makeBooking(booking); err != nil {
+			return err
+		}
+
+		if !repository.reserve(tireChangeTime, booking) {
+			return newUnAvailableBookingError(tireChangeTime)
+		}
+
+		return nil
+	})
+}
+
+// newBooking books tire change of a passenger car for waitlisted contact at the price of assignment time
+func (s *waitlistService) newBooking(
+	tireChangeTime *tireChangeTimeEntity,
+	entry *waitlistEntryEntity,
+) *tireChangeBookingEntity {
+	serviceType := s.serviceTypes.oneByCode(defaultServiceTypeCode)
+
+	return newTireChangeBookingEntity(
+		tireChangeTime,
+		entry.ContactInformation,
+		serviceType.Code,
+		defaultVehicleType,
+		s.pricing.quote(serviceType, defaultVehicleType, tireChangeTime.Time),
+	)
+}
+
+func (s *waitlistService) newWaitlistEntryResponse(entry *waitlistEntryEntity) *waitlistEntryResponse {
+	if entry.Status != waitlistStatusAssigned {
+		return newWaitlistEntryResponse(entry, nil)
+	}
+
+	return newWaitlistEntryResponse(entry, s.tireChangeTimeRepository.oneByUUID(entry.AssignedTireChangeTimeUUID))
+}
+
+// tireHotelService keeps track of customers tire sets stored by the workshop
+type tireHotelService struct {
+	repository *tireSetRepository
+}
+
+func newTireHotelService(repository *tireSetRepository) *tireHotelService {
+	return &tireHotelService{repository: repository}
+}
+
+func (s *tireHotelService) register(request *tireSetRequest) *tireSetResponse {
+	log.Infof("registering tire set for request: %+v", request)
+	tireSet := s.repository.save(newTireSetEntity(
+		request.ContactInformation,
+		request.Size,
+		request.Brand,
+		request.Condition,
+		request.StorageLocation,
+	))
+	log.Infof("successfully registered tire set %s at %s", tireSet.UUID, tireSet.StorageLocation)
+
+	return newTireSetResponse(tireSet)
+}
+
+func (s *tireHotelService) get(uuid string) (*tireSetResponse, error) {
+	tireSet := s.repository.oneByUUID(uuid)
+
+	if tireSet == zeroTireSetEntity {
+		return nil, newUnknownTireSetError(uuid)
+	}
+
+	return newTireSetResponse(tireSet), nil
+}
+
+func (s *tireHotelService) getByContact(query *tireSetsSearchQuery) *tireSetsResponse {
+	return newTireSetsResponse(s.repository.allByContact(query.ContactInformation))
+}
+
+// workshopService publishes workshop details for API clients
+type workshopService struct {
+	info    WorkshopInfo
+	pricing *pricingEngine
+}
+
+func newWorkshopService(info WorkshopInfo, pricing *pricingEngine) *workshopService {
+	return &workshopService{info: info, pricing: pricing}
+}
+
+func (s *workshopService) get() *workshopResponse {
+	return newWorkshopResponse(s.info, s.pricing.vehicleTypes())
+}
+
+// staffService manages mechanics, whose shifts and absences limit bays available at tire change times
+type staffService struct {
+	repository               *mechanicRepository
+	tireChangeTimeRepository *tireChangeTimeRepository
+	waitlist                 *waitlistService
+	baysPerTimeSlot          uint
+}
+
+func newStaffService(
+	repository *mechanicRepository,
+	tireChangeTimeRepository *tireChangeTimeRepository,
+	waitlist *waitlistService,
+	baysPerTimeSlot uint,
+) *staffService {
+	return &staffService{
+		repository:               repository,
+		tireChangeTimeRepository: tireChangeTimeRepository,
+		waitlist:                 waitlist,
+		baysPerTimeSlot:          baysPerTimeSlot,
+	}
+}
+
+func (s *staffService) getAll() *mechanicsResponse {
+	return newMechanicsResponse(s.repository.all())
+}
+
+func (s *staffService) register(request *mechanicRequest) *mechanicResponse {
+	mechanic := s.repository.save(newMechanicEntity(request.Name))
+	log.Infof("successfully registered mechanic %s", mechanic.UUID)
+
+	return newMechanicResponse(mechanic)
+}
+
+// replaceShifts sets weekly shifts of the mechanic, capacity of all future tire change times is adjusted accordingly
+func (s *staffService) replaceShifts(uuid string, request *mechanicShiftsRequest) (*mechanicResponse, error) {
+	mechanic := s.repository.oneByUUID(uuid)
+
+	if mechanic == zeroMechanicEntity {
+		return nil, newUnknownMechanicError(uuid)
+	}
+
+	shifts := make([]*mechanicShiftEntity, 0, len(request.Shifts))
+
+	for _, shift := range request.Shifts {
+		shifts = append(shifts, newMechanicShiftEntity(mechanic, shift.weekday(), shift.StartHour, shift.EndHour))
+	}
+
+	mechanic = s.repository.replaceShifts(mechanic, shifts)
+	log.Infof("successfully replaced shifts of mechanic %s", mechanic.UUID)
+	s.adjustCapacity(time.Now(), time.Time{})
+
+	return newMechanicResponse(mechanic), nil
+}
+
+// registerAbsence takes mechanic off duty for a day, returning bookings flagged for rescheduling
+func (s *staffService) registerAbsence(uuid string, request *mechanicAbsenceRequest) (*mechanicAbsenceResponse, error) {
+	mechanic := s.repository.oneByUUID(uuid)
+
+	if mechanic == zeroMechanicEntity {
+		return nil, newUnknownMechanicError(uuid)
+	}
+
+	absence := newMechanicAbsenceEntity(mechanic, request.day(), request.Type)
+	mechanic.Absences = append(mechanic.Absences, absence)
+	s.repository.save(mechanic)
+	log.Infof("registered %s of mechanic %s on %s", absence.Type, mechanic.UUID, request.Date)
+
+	response := newMechanicAbsenceResponse(absence)
+	response.RescheduledBookings = newRescheduledBookingResponses(
+		s.adjustCapacity(absence.Date, absence.Date.AddDate(0, 0, 1)),
+	)
+
+	return response, nil
+}
+
+// removeAbsence returns mechanic on duty, released bays are offered to waitlisted contacts
+func (s *staffService) removeAbsence(uuid string, absenceUUID string) (*mechanicResponse, error) {
+	mechanic := s.repository.oneByUUID(uuid)
+
+	if mechanic == zeroMechanicEntity {
+		return nil, newUnknownMechanicError(uuid)
+	}
+
+	absence := mechanic.absenceOf(absenceUUID)
+
+	if absence == nil {
+		return nil, newUnknownMechanicAbsenceError(mechanic, absenceUUID)
+	}
+
+	s.repository.deleteAbsence(absence)
+	log.Infof("removed absence %s of mechanic %s", absence.UUID, mechanic.UUID)
+	s.adjustCapacity(absence.Date, absence.Date.AddDate(0, 0, 1))
+
+	return newMechanicResponse(s.repository.oneByUUID(uuid)), nil
+}
+
+func (s *staffService) getRescheduledBookings() *rescheduledBookingsResponse {
+	return &rescheduledBookingsResponse{
+		Bookings: newRescheduledBookingResponses(s.tireChangeTimeRepository.allRequiringRescheduling(time.Now())),
+	}
+}
+
+// adjustCapacity staffs upcoming tire change times of given period, zero until includes all future times
+func (s *staffService) adjustCapacity(from time.Time, until time.Time) []*tireChangeTimeEntity {
+	if now := time.Now(); from.Before(now) {
+		from = now
+	}
+
+	mechanics := s.repository.all()
+	tireChangeTimes := s.tireChangeTimeRepository.allByTimeRange(from, until)
+
+	for _, tireChangeTime := range tireChangeTimes {
+		wasAvailable := tireChangeTime.Available
+
+		if !tireChangeTime.staff(s.staffedBays(mechanics, tireChangeTime.Time)) {
+			continue
+		}
+
+		s.tireChangeTimeRepository.save(tireChangeTime)
+
+		if !wasAvailable && tireChangeTime.Available {
+			s.waitlist.offer(tireChangeTime)
+		}
+	}
+
+	log.Infof("adjusted capacity of %d tire change times from %s", len(tireChangeTimes), from)
+
+	return tireChangeTimes
+}
+
+// staffedBays returns the amount of bays operated by mechanics on duty at given time, limited by bays of the workshop
+func (s *staffService) staffedBays(mechanics []*mechanicEntity, t time.Time) uint {
+	onDuty := uint(0)
+
+	for _, mechanic := range mechanics {
+		if mechanic.onDuty(t) {
+			onDuty++
+		}
+	}
+
+	if onDuty > s.baysPerTimeSlot {
+		return s.baysPerTimeSlot
+	}
+
+	return onDuty
+}
+
+// bookingRules validates workshop constraints applied to every new booking on top of tire change time availability
+type bookingRules struct {
+	config Config
+}
+
+func newBookingRules(config Config) *bookingRules {
+	return &bookingRules{config: config}
+}
+
+// check verifies whether contact is allowed to book given tire change time within transaction of given repository
+func (r *bookingRules) check(
+	repository *tireChangeTimeRepository,
+	tireChangeTime *tireChangeTimeEntity,
+	contactInformation string,
+) error {
+	if !tireChangeTime.Available || tireChangeTime.bookingOf(contactInformation) != nil {
+		return nil
+	}
+
+	now := time.Now()
+
+	if err := tireChangeTime.checkBookingWindow(now, r.config.MinBookingLeadTime, r.config.MaxBookingAdvance); err != nil {
+		return err
+	}
+
+	if r.config.MaxActiveBookingsPerContact > 0 &&
+		repository.countActiveByContact(contactInformation, now) >= r.config.MaxActiveBookingsPerContact {
+		return newBookingLimitExceededError(contactInformation, r.config.MaxActiveBookingsPerContact)
+	}
+
+	return nil
+}
+
+// pricingEngine quotes service prices in pennies by vehicle type and time of the service
+type pricingEngine struct {
+	vehicleTypeFactors map[string]int64
+	peakHours          map[int]bool
+	peakHourSurcharge  int64
+	peakSeasonMonths   map[time.Month]bool
+	seasonalSurcharge  int64
+	weekendSurcharge   int64
+}
+
+// newPricingEngine creates pricing engine with given workshop price list
+func newPricingEngine(pricing Pricing) *pricingEngine {
+	engine := &pricingEngine{
+		vehicleTypeFactors: pricing.VehicleTypeFactors,
+		peakHours:          map[int]bool{},
+		peakHourSurcharge:  pricing.PeakHourSurcharge,
+		peakSeasonMonths:   map[time.Month]bool{},
+		seasonalSurcharge:  pricing.SeasonalSurcharge,
+		weekendSurcharge:   pricing.WeekendSurcharge,
+	}
+
+	for _, hour := range pricing.PeakHours {
+		engine.peakHours[hour] = true
+	}
+
+	for _, month := range pricing.PeakSeasonMonths {
+		engine.peakSeasonMonths[month] = true
+	}
+
+	return engine
+}
+
+// vehicleTypes returns all vehicle types priced by the workshop in alphabetical order
+func (e *pricingEngine) vehicleTypes() []string {
+	vehicleTypes := make([]string, 0, len(e.vehicleTypeFactors))
+
+	for vehicleType := range e.vehicleTypeFactors {
+		vehicleTypes = append(vehicleTypes, vehicleType)
+	}
+
+	sort.Strings(vehicleTypes)
+
+	return vehicleTypes
+}
+
+func (e *pricingEngine) supports(vehicleType string) bool {
+	_, ok := e.vehicleTypeFactors[vehicleType]
+
+	return ok
+}
+
+// quote calculates price of the service in pennies, rounded half up
+func (e *pricingEngine) quote(serviceType *serviceTypeEntity, vehicleType string, startTime time.Time) int64 {
+	startTime = shared.InServerTimeZone(startTime)
+	surcharge := int64(0)
+
+	if e.peakHours[startTime.Hour()] {
+		surcharge += e.peakHourSurcharge
+	}
+
+	if e.peakSeasonMonths[startTime.Month()] {
+		surcharge += e.seasonalSurcharge
+	}
+
+	if startTime.Weekday() == time.Saturday || startTime.Weekday() == time.Sunday {
+		surcharge += e.weekendSurcharge
+	}
+
+	// factor and surcharge are percents, the product is scaled back from percents of percents
+	return (serviceType.PricePennies*e.vehicleTypeFactors[vehicleType]*(100+surcharge) + 5000) / 10000
 }
@@ -1,31 +1,177 @@
 package manchester
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
-const v2Path = "/api/v2"
+const (
+	v2Path     = "/api/v2"
+	branchPath = "/branches/:branchId"
+)
 
 type controller struct {
-	service *tireChangeTimesService
+	service   *tireChangeTimesService
+	waitlist  *waitlistService
+	tireHotel *tireHotelService
+	workshop  *workshopService
+	staff     *staffService
+}
+
+func newController(
+	service *tireChangeTimesService,
+	waitlist *waitlistService,
+	tireHotel *tireHotelService,
+	workshop *workshopService,
+	staff *staffService,
+) *controller {
+	return &controller{service: service, waitlist: waitlist, tireHotel: tireHotel, workshop: workshop, staff: staff}
+}
+
+// registerController registers routes of every branch under /branches/{branchId}/api/v2,
+// default branch routes are aliased by /api/v2. Admin routes are registered only when enabled
+func registerController(
+	router *gin.Engine,
+	defaultBranch *controller,
+	branches map[string]*controller,
+	adminAPI bool,
+) {
+	defaultV2 := router.Group(v2Path, branchMiddleware(map[string]*controller{"": defaultBranch}))
+	branchV2 := router.Group(branchPath+v2Path, branchMiddleware(branches))
+
+	registerBranchRoutes(defaultV2)
+	registerBranchRoutes(branchV2)
+
+	if adminAPI {
+		registerAdminRoutes(defaultV2)
+		registerAdminRoutes(branchV2)
+	}
+
+	router.GET(v2Path+"/branches", func(ctx *gin.Context) { getBranches(ctx, branches) })
+}
+
+func registerBranchRoutes(router gin.IRoutes) {
+	router.GET("/workshop", handle((*controller).getWorkshop))
+	router.GET("/service-types", handle((*controller).getServiceTypes))
+	router.GET("/tire-change-times", handle((*controller).getTireChangeTimes))
+	router.GET("/tire-change-times/next-available", handle((*controller).getNextAvailableTireChangeTimes))
+	router.GET("/tire-change-times/daily-availability", handle((*controller).getDailyAvailability))
+	router.GET("/tire-change-times/:id", handle((*controller).getTireChangeTime))
+	router.POST("/tire-change-times/:id/booking", handle((*controller).postTireChangeBooking))
+	router.DELETE("/tire-change-times/:id/booking", handle((*controller).deleteTireChangeBooking))
+	router.PUT("/tire-change-times/:id/booking/tire-set", handle((*controller).putTireChangeBookingTireSet))
+	router.POST("/waitlist", handle((*controller).postWaitlistEntry))
+	router.GET("/waitlist/:id", handle((*controller).getWaitlistEntry))
+	router.POST("/tire-sets", handle((*controller).postTireSet))
+	router.GET("/tire-sets", handle((*controller).getTireSets))
+	router.GET("/tire-sets/:id", handle((*controller).getTireSet))
+}
+
+// registerAdminRoutes registers management of mechanic shifts and absences
+func registerAdminRoutes(router gin.IRoutes) {
+	router.GET("/admin/mechanics", handle((*controller).getMechanics))
+	router.POST("/admin/mechanics", handle((*controller).postMechanic))
+	router.PUT("/admin/mechanics/:id/shifts", handle((*controller).putMechanicShifts))
+	router.POST("/admin/mechanics/:id/absences", handle((*controller).postMechanicAbsence))
+	router.DELETE("/admin/mechanics/:id/absences/:absenceId", handle((*controller).deleteMechanicAbsence))
+	router.GET("/admin/rescheduled-bookings", handle((*controller).getRescheduledBookings))
 }
 
-func registerController(router *gin.Engine, service *tireChangeTimesService) {
-	c := &controller{service: service}
+// handle dispatches request to the controller of branch resolved by branchMiddleware
+func handle(action func(*controller, *gin.Context)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		action(ctx.MustGet(branchContextKey).(*controller), ctx)
+	}
+}
 
-	router.GET(v2Path+"/tire-change-times", c.getTireChangeTimes)
-	router.POST(v2Path+"/tire-change-times/:id/booking", c.postTireChangeBooking)
+// render writes response in the format negotiated by Accept header, JSON is used when header is missing
+func render(ctx *gin.Context, code int, obj interface{}) {
+	switch ctx.NegotiateFormat(gin.MIMEJSON, gin.MIMEXML, gin.MIMEXML2) {
+	case gin.MIMEXML, gin.MIMEXML2:
+		ctx.XML(code, obj)
+	default:
+		ctx.JSON(code, obj)
+	}
+}
+
+// bind decodes request body by its Content-Type, JSON is assumed when header is missing
+func bind(ctx *gin.Context, obj interface{}) error {
+	switch ctx.ContentType() {
+	case gin.MIMEXML, gin.MIMEXML2:
+		return ctx.ShouldBindXML(obj)
+	default:
+		return ctx.ShouldBindJSON(obj)
+	}
+}
+
+// getBranches godoc
+// @Summary List of workshop branches hosted by the server
+// @Description Every branch serves the same API under /branches/{branchId}/api/v2 with its own schedule and bookings,
+// @Description /api/v2 routes serve the default branch.
+// @Accept json,xml
+// @Produce json,xml
+// @Success 200 {object} branchesResponse
+// @Failure 500 {object} errorResponse
+// @Router /branches [get]
+func getBranches(ctx *gin.Context, branches map[string]*controller) {
+	workshops := make(map[string]*workshopResponse, len(branches))
+
+	for id, branch := range branches {
+		workshops[id] = branch.workshop.get()
+	}
+
+	render(ctx, http.StatusOK, newBranchesResponse(workshops))
+}
+
+// getWorkshop godoc
+// @Summary Workshop details with location, opening hours, time zone and serviced vehicle types
+// @Accept json,xml
+// @Produce json,xml
+// @Success 200 {object} workshopResponse
+// @Failure 500 {object} errorResponse
+// @Router /workshop [get]
+func (c *controller) getWorkshop(ctx *gin.Context) {
+	render(ctx, http.StatusOK, c.workshop.get())
+}
+
+// getServiceTypes godoc
+// @Summary List of services provided by the workshop
+// @Accept json,xml
+// @Produce json,xml
+// @Success 200 {object} serviceTypesResponse
+// @Failure 500 {object} errorResponse
+// @Router /service-types [get]
+func (c *controller) getServiceTypes(ctx *gin.Context) {
+	render(ctx, http.StatusOK, c.service.getServiceTypes())
 }
 
 // getTireChangeTimes godoc
 // @Summary List of available tire change times
-// @Accept json
-// @Produce json
+// @Description Every tire change time is quoted with the price of given service for given vehicle type.
+// @Description Tire change times of bays restricted to certain vehicle types list them, others service all types.
+// @Accept json,xml
+// @Produce json,xml
 // @Param amount query integer false "amount of tire change times per page"
 // @Param page query integer false "The number of pages to skip before starting to collect the result set"
+// @Param limit query integer false "amount of tire change times per cursor paginated page, can not be combined with amount"
+// @Param cursor query string false "opaque position of cursor paginated page given by Link header, requires limit"
 // @Param from query string false "search tire change times from date" Format(date) default(2006-01-02)
+// @Param until query string false "search tire change times until date inclusive, must not be before from date" Format(date) default(2030-01-02)
+// @Param timeFrom query string false "list only tire change times starting at or after the time of day in server time zone" default(08:00)
+// @Param timeUntil query string false "list only tire change times starting before the time of day in server time zone" default(12:00)
+// @Param weekdays query []string false "list only tire change times of the weekdays" collectionFormat(multi) Enums(MONDAY, TUESDAY, WEDNESDAY, THURSDAY, FRIDAY, SATURDAY, SUNDAY)
+// @Param available query boolean false "list only available or only booked tire change times, ignored when serviceType is given"
+// @Param serviceType query string false "list only available start times fitting the service, see service types list"
+// @Param vehicleType query string false "list only tire change times able to service the vehicle type and quote prices for it, defaults to CAR pricing" Enums(CAR, SUV, VAN)
 // @Success 200 {object} tireChangeTimesResponse
+// @Header 200 {string} ETag "Entity tag of the response, send it by If-None-Match header to get 304 when unchanged"
+// @Header 200 {string} Last-Modified "Latest change of tire change times, send it by If-Modified-Since header to get 304 when unchanged"
+// @Header 200 {integer} X-Total-Count "Total amount of tire change times matching the search"
+// @Header 200 {string} Link "RFC 8288 links to prev and next pages of paginated search"
 // @Failure 400 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /tire-change-times [get]
@@ -36,18 +182,156 @@ func (c *controller) getTireChangeTimes(ctx *gin.Context) {
 		panic(newValidationError(err))
 	}
 
-	ctx.JSON(http.StatusOK, c.service.get(&query))
+	response, pagination, err := c.service.get(&query)
+
+	if err != nil {
+		panic(err)
+	}
+
+	writePaginationHeaders(ctx, pagination)
+	ctx.Header("Last-Modified", c.service.lastModified().UTC().Format(http.TimeFormat))
+	render(ctx, http.StatusOK, response)
+}
+
+// writePaginationHeaders publishes total amount of found tire change times by X-Total-Count header and
+// links to neighbouring pages by RFC 8288 Link header, pages are linked by cursor regardless of requested pagination
+func writePaginationHeaders(ctx *gin.Context, pagination *tireChangeTimesPagination) {
+	ctx.Header("X-Total-Count", strconv.FormatUint(uint64(pagination.total), 10))
+
+	var links []string
+
+	if pagination.prev != nil {
+		links = append(links, pageLink(ctx, pagination.prev, pagination.limit, "prev"))
+	}
+
+	if pagination.next != nil {
+		links = append(links, pageLink(ctx, pagination.next, pagination.limit, "next"))
+	}
+
+	if len(links) > 0 {
+		ctx.Header("Link", strings.Join(links, ", "))
+	}
+}
+
+func pageLink(ctx *gin.Context, cursor *tireChangeTimesCursor, limit uint, rel string) string {
+	values := ctx.Request.URL.Query()
+	values.Del("amount")
+	values.Del("page")
+	values.Set("limit", strconv.FormatUint(uint64(limit), 10))
+	values.Set("cursor", cursor.String())
+
+	link := url.URL{Path: ctx.Request.URL.Path, RawQuery: values.Encode()}
+
+	return fmt.Sprintf(`<%s>; rel="%s"`, link.String(), rel)
+}
+
+// getDailyAvailability godoc
+// @Summary Daily counts of tire change times
+// @Description Counts all, available and booked tire change times of every day having them in server time zone.
+// @Description Upcoming tire change time having free places left is counted as available, tire change time having
+// @Description all places booked as booked. Passed and closed tire change times are counted only in total.
+// @Accept json,xml
+// @Produce json,xml
+// @Param from query string true "summarize tire change times from date" Format(date) default(2006-01-02)
+// @Param until query string true "summarize tire change times until date inclusive, must not be before from date" Format(date) default(2030-01-02)
+// @Param vehicleType query string false "count only tire change times able to service the vehicle type" Enums(CAR, SUV, VAN)
+// @Success 200 {object} dailyAvailabilityResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /tire-change-times/daily-availability [get]
+func (c *controller) getDailyAvailability(ctx *gin.Context) {
+	var query dailyAvailabilityQuery
+
+	if err := ctx.ShouldBind(&query); err != nil {
+		panic(newValidationError(err))
+	}
+
+	response, err := c.service.getDailyAvailability(&query)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, response)
+}
+
+// getNextAvailableTireChangeTimes godoc
+// @Summary Earliest available tire change times after given time
+// @Description Tire change times are quoted with the price of the default service for given vehicle type.
+// @Accept json,xml
+// @Produce json,xml
+// @Param after query string false "list tire change times starting after the time, defaults to now" Format(date-time) default(2006-01-02T15:04:05Z)
+// @Param count query integer false "amount of tire change times to return" minimum(1) maximum(50) default(1)
+// @Param vehicleType query string false "list only tire change times able to service the vehicle type and quote prices for it, defaults to CAR pricing" Enums(CAR, SUV, VAN)
+// @Success 200 {object} tireChangeTimesResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /tire-change-times/next-available [get]
+func (c *controller) getNextAvailableTireChangeTimes(ctx *gin.Context) {
+	var query nextAvailableQuery
+
+	if err := ctx.ShouldBind(&query); err != nil {
+		panic(newValidationError(err))
+	}
+
+	response, err := c.service.getNextAvailable(&query)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, response)
+}
+
+// getTireChangeTime godoc
+// @Summary Single tire change time with its availability and remaining capacity
+// @Description Tire change time is quoted with the price of given service for given vehicle type.
+// @Accept json,xml
+// @Produce json,xml
+// @Param id path integer true "tire change time ID"
+// @Param serviceType query string false "quote price of the service, see service types list"
+// @Param vehicleType query string false "quote price for the vehicle type, defaults to CAR pricing" Enums(CAR, SUV, VAN)
+// @Success 200 {object} tireChangeTimeBookingResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /tire-change-times/{id} [get]
+func (c *controller) getTireChangeTime(ctx *gin.Context) {
+	var uri tireChangeTimeURI
+	var query tireChangeTimeQuery
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(newValidationError(err))
+	} else if err := ctx.ShouldBind(&query); err != nil {
+		panic(newValidationError(err))
+	}
+
+	response, err := c.service.getByID(uri.ID, &query)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, response)
 }
 
 // postTireChangeBooking godoc
 // @Summary Book tire change time
-// @Accept json
-// @Produce json
+// @Description Repeated booking by the same contact depends on server --booking-policy option:
+// @Description "strict" (default) rejects it with error code 22 as any other unavailable time,
+// @Description "idempotent" responds with the already booked time like London workshop does.
+// @Description Services lasting longer than a single tire change time reserve consecutive tire change times,
+// @Description tire change service is booked when service type is not given.
+// @Description Quoted price is agreed with the booking and returned in the response.
+// @Description Tire set of the contact stored in tire hotel can be attached to be fetched before the appointment,
+// @Description repeated booking keeps the tire set of the booking, use booking/tire-set endpoint to change it.
+// @Accept json,xml
+// @Produce json,xml
 // @Param id path integer true "available tire change time ID"
 // @Param body body tireChangeBookingRequest true "Request body"
 // @Success 200 {object} tireChangeTimeBookingResponse
 // @Failure 400 {object} errorResponse
-// @Failure 422 {object} errorResponse "The tire change time has already been booked"
+// @Failure 422 {object} errorResponse "The tire change time has already been booked, is not followed by enough available times for the service, overlaps another booking of the contact, is outside of booking window, contact has reached active bookings limit, tire set is not stored for the contact or vehicle type is not supported by the bay"
 // @Failure 500 {object} errorResponse
 // @Router /tire-change-times/{id}/booking [post]
 func (c *controller) postTireChangeBooking(ctx *gin.Context) {
@@ -56,15 +340,325 @@ func (c *controller) postTireChangeBooking(ctx *gin.Context) {
 
 	if err := ctx.ShouldBindUri(&uri); err != nil {
 		panic(newValidationError(err))
-	} else if err := ctx.ShouldBindJSON(&request); err != nil {
+	} else if err := bind(ctx, &request); err != nil {
+		panic(newValidationError(err))
+	}
+
+	response, err := c.service.book(uri.ID, &request)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, response)
+}
+
+// putTireChangeBookingTireSet godoc
+// @Summary Attach tire set stored in tire hotel to booked tire change time
+// @Description Tire set is attached to all tire change times reserved together by the booking.
+// @Accept json,xml
+// @Produce json,xml
+// @Param id path integer true "booked tire change time ID"
+// @Param body body tireChangeBookingTireSetRequest true "Request body"
+// @Success 200 {object} tireChangeTimeBookingResponse
+// @Failure 400 {object} errorResponse
+// @Failure 422 {object} errorResponse "The tire change time is not booked by given contact or tire set is not stored for the contact"
+// @Failure 500 {object} errorResponse
+// @Router /tire-change-times/{id}/booking/tire-set [put]
+func (c *controller) putTireChangeBookingTireSet(ctx *gin.Context) {
+	var uri tireChangeBookingURI
+	var request tireChangeBookingTireSetRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(newValidationError(err))
+	} else if err := bind(ctx, &request); err != nil {
+		panic(newValidationError(err))
+	}
+
+	response, err := c.service.attachTireSet(uri.ID, &request)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, response)
+}
+
+// deleteTireChangeBooking godoc
+// @Summary Cancel tire change time booking, released times are assigned to the first matching waitlist entries
+// @Accept json,xml
+// @Produce json,xml
+// @Param id path integer true "booked tire change time ID"
+// @Param body body tireChangeBookingCancellationRequest true "Request body"
+// @Success 200 {object} tireChangeTimeBookingResponse
+// @Failure 400 {object} errorResponse
+// @Failure 422 {object} errorResponse "The tire change time is not booked by given contact"
+// @Failure 500 {object} errorResponse
+// @Router /tire-change-times/{id}/booking [delete]
+func (c *controller) deleteTireChangeBooking(ctx *gin.Context) {
+	var uri tireChangeBookingURI
+	var request tireChangeBookingCancellationRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(newValidationError(err))
+	} else if err := bind(ctx, &request); err != nil {
+		panic(newValidationError(err))
+	}
+
+	response, err := c.service.cancelBooking(uri.ID, request.ContactInformation)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, response)
+}
+
+// postWaitlistEntry godoc
+// @Summary Register interest in fully booked tire change time or day
+// @Description Either tireChangeTimeId or date must be given. When matching time is free it is booked right away,
+// @Description otherwise it is assigned to the contact once it gets released.
+// @Accept json,xml
+// @Produce json,xml
+// @Param body body waitlistRequest true "Request body"
+// @Success 200 {object} waitlistEntryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 422 {object} errorResponse "The tire change time does not exist"
+// @Failure 500 {object} errorResponse
+// @Router /waitlist [post]
+func (c *controller) postWaitlistEntry(ctx *gin.Context) {
+	var request waitlistRequest
+
+	if err := bind(ctx, &request); err != nil {
+		panic(newValidationError(err))
+	}
+
+	response, err := c.waitlist.register(&request)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, response)
+}
+
+// getWaitlistEntry godoc
+// @Summary Waitlist entry status with assigned tire change time
+// @Accept json,xml
+// @Produce json,xml
+// @Param id path integer true "waitlist entry ID"
+// @Success 200 {object} waitlistEntryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /waitlist/{id} [get]
+func (c *controller) getWaitlistEntry(ctx *gin.Context) {
+	var uri waitlistEntryURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(newValidationError(err))
+	}
+
+	response, err := c.waitlist.get(uri.ID)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, response)
+}
+
+// postTireSet godoc
+// @Summary Register tire set stored in tire hotel for the contact
+// @Accept json,xml
+// @Produce json,xml
+// @Param body body tireSetRequest true "Request body"
+// @Success 200 {object} tireSetResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /tire-sets [post]
+func (c *controller) postTireSet(ctx *gin.Context) {
+	var request tireSetRequest
+
+	if err := bind(ctx, &request); err != nil {
+		panic(newValidationError(err))
+	}
+
+	render(ctx, http.StatusOK, c.tireHotel.register(&request))
+}
+
+// getTireSets godoc
+// @Summary List of tire sets stored in tire hotel for the contact
+// @Accept json,xml
+// @Produce json,xml
+// @Param contactInformation query string true "contact owning tire sets"
+// @Success 200 {object} tireSetsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /tire-sets [get]
+func (c *controller) getTireSets(ctx *gin.Context) {
+	var query tireSetsSearchQuery
+
+	if err := ctx.ShouldBind(&query); err != nil {
+		panic(newValidationError(err))
+	}
+
+	render(ctx, http.StatusOK, c.tireHotel.getByContact(&query))
+}
+
+// getTireSet godoc
+// @Summary Tire set stored in tire hotel
+// @Accept json,xml
+// @Produce json,xml
+// @Param id path integer true "tire set ID"
+// @Success 200 {object} tireSetResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /tire-sets/{id} [get]
+func (c *controller) getTireSet(ctx *gin.Context) {
+	var uri tireSetURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(newValidationError(err))
+	}
+
+	response, err := c.tireHotel.get(uri.ID)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, response)
+}
+
+// getMechanics godoc
+// @Summary List of workshop mechanics with their weekly shifts and absences
+// @Accept json,xml
+// @Produce json,xml
+// @Success 200 {object} mechanicsResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/mechanics [get]
+func (c *controller) getMechanics(ctx *gin.Context) {
+	render(ctx, http.StatusOK, c.staff.getAll())
+}
+
+// postMechanic godoc
+// @Summary Register workshop mechanic, mechanic is not on duty until shifts are set
+// @Accept json,xml
+// @Produce json,xml
+// @Param body body mechanicRequest true "Request body"
+// @Success 200 {object} mechanicResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/mechanics [post]
+func (c *controller) postMechanic(ctx *gin.Context) {
+	var request mechanicRequest
+
+	if err := bind(ctx, &request); err != nil {
 		panic(newValidationError(err))
 	}
 
-	response, err := c.service.book(uri.ID, request.ContactInformation)
+	render(ctx, http.StatusOK, c.staff.register(&request))
+}
+
+// putMechanicShifts godoc
+// @Summary Replace weekly shifts of the mechanic
+// @Description Every mechanic on duty operates single bay, so the amount of bookable places of all future tire change
+// @Description times is recalculated. Bookings left without mechanic are flagged for rescheduling.
+// @Accept json,xml
+// @Produce json,xml
+// @Param id path integer true "mechanic ID"
+// @Param body body mechanicShiftsRequest true "Request body"
+// @Success 200 {object} mechanicResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/mechanics/{id}/shifts [put]
+func (c *controller) putMechanicShifts(ctx *gin.Context) {
+	var uri mechanicURI
+	var request mechanicShiftsRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(newValidationError(err))
+	} else if err := bind(ctx, &request); err != nil {
+		panic(newValidationError(err))
+	}
+
+	mechanic, err := c.staff.replaceShifts(uri.ID, &request)
 
 	if err != nil {
 		panic(err)
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	render(ctx, http.StatusOK, mechanic)
+}
+
+// postMechanicAbsence godoc
+// @Summary Register day off or sick leave of the mechanic
+// @Description Bookings of the day left without mechanic are flagged for rescheduling and returned in the response.
+// @Accept json,xml
+// @Produce json,xml
+// @Param id path integer true "mechanic ID"
+// @Param body body mechanicAbsenceRequest true "Request body"
+// @Success 200 {object} mechanicAbsenceResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/mechanics/{id}/absences [post]
+func (c *controller) postMechanicAbsence(ctx *gin.Context) {
+	var uri mechanicURI
+	var request mechanicAbsenceRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(newValidationError(err))
+	} else if err := bind(ctx, &request); err != nil {
+		panic(newValidationError(err))
+	}
+
+	absence, err := c.staff.registerAbsence(uri.ID, &request)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, absence)
+}
+
+// deleteMechanicAbsence godoc
+// @Summary Cancel absence of the mechanic, released places are assigned to the first matching waitlist entries
+// @Accept json,xml
+// @Produce json,xml
+// @Param id path integer true "mechanic ID"
+// @Param absenceId path integer true "absence ID"
+// @Success 200 {object} mechanicResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/mechanics/{id}/absences/{absenceId} [delete]
+func (c *controller) deleteMechanicAbsence(ctx *gin.Context) {
+	var uri mechanicAbsenceURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		panic(newValidationError(err))
+	}
+
+	mechanic, err := c.staff.removeAbsence(uri.ID, uri.AbsenceID)
+
+	if err != nil {
+		panic(err)
+	}
+
+	render(ctx, http.StatusOK, mechanic)
+}
+
+// getRescheduledBookings godoc
+// @Summary List of upcoming bookings left without mechanic, contacts have to be offered another time
+// @Accept json,xml
+// @Produce json,xml
+// @Success 200 {object} rescheduledBookingsResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/rescheduled-bookings [get]
+func (c *controller) getRescheduledBookings(ctx *gin.Context) {
+	render(ctx, http.StatusOK, c.staff.getRescheduledBookings())
 }
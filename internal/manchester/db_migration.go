@@ -1,6 +1,7 @@
 package manchester
 
 import (
+	"fmt"
 	"github.com/jinzhu/gorm"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/gormigrate.v1"
@@ -35,7 +36,12 @@ var initial = &gormigrate.Migration{
 
 			for i := 0; i < 1500; i++ {
 				nextTime = calculateTireChangeTime(nextTime.Add(time.Hour * time.Duration(1)))
-				err = db.Create(newTireChangeTimeEntity(nextTime, calculateAvailability(nextTime))).Error
+				err = db.Table(tireChangeTimeEntity{}.TableName()).Create(&tireChangeTimeEntityVersion1{
+					Time:      nextTime,
+					Available: calculateAvailability(nextTime),
+					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
+				}).Error
 			}
 		}
 
@@ -80,3 +86,426 @@ func calculateAvailability(tireChangeTime time.Time) bool {
 
 	return dateSum%5 > 0
 }
+
+var addWaitlist = &gormigrate.Migration{
+	ID: "202610190901",
+
+	Migrate: func(db *gorm.DB) error {
+		type waitlistEntryEntityVersion1 struct {
+			ID uint `gorm:"primary_key"`
+
+			ContactInformation string
+
+			TireChangeTimeID uint
+			Date             time.Time
+
+			Status string `gorm:"index"`
+
+			AssignedTireChangeTimeID uint
+
+			CreatedAt time.Time
+			UpdatedAt time.Time
+		}
+
+		err := db.Table(waitlistEntryEntity{}.TableName()).CreateTable(&waitlistEntryEntityVersion1{}).Error
+
+		if err == nil {
+			log.Info("Migrated 202610190901")
+		}
+
+		return err
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.DropTable(waitlistEntryEntity{}.TableName()).Error
+	},
+}
+
+// addCapacity allows multiple bookings per tire change time, existing bookings are moved to separate table
+func addCapacity(baysPerTimeSlot uint) *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610191001",
+
+		Migrate: func(db *gorm.DB) error {
+			type tireChangeTimeEntityVersion2 struct {
+				Capacity    uint
+				BookedCount uint
+			}
+
+			type tireChangeBookingEntityVersion1 struct {
+				ID uint `gorm:"primary_key"`
+
+				TireChangeTimeID uint `gorm:"index"`
+
+				ContactInformation string `gorm:"index"`
+
+				CreatedAt time.Time
+				UpdatedAt time.Time
+			}
+
+			err := db.Table(tireChangeTimeEntity{}.TableName()).AutoMigrate(&tireChangeTimeEntityVersion2{}).Error
+
+			if err == nil {
+				err = db.Table(tireChangeBookingEntity{}.TableName()).CreateTable(&tireChangeBookingEntityVersion1{}).Error
+			}
+
+			if err == nil {
+				err = db.Exec(
+					"INSERT INTO tire_change_booking (tire_change_time_id, contact_information, created_at, updated_at) "+
+						"SELECT id, booked_by_contact, updated_at, updated_at FROM tire_change_time "+
+						"WHERE available = ? AND booked_by_contact <> ''",
+					false,
+				).Error
+			}
+
+			if err == nil {
+				err = db.Exec(
+					"UPDATE tire_change_time SET capacity = ?, booked_count = CASE WHEN available = ? THEN 0 ELSE ? END",
+					baysPerTimeSlot,
+					true,
+					baysPerTimeSlot,
+				).Error
+			}
+
+			if err == nil {
+				log.Info("Migrated 202610191001")
+			}
+
+			return err
+		},
+
+		Rollback: func(tx *gorm.DB) error {
+			return tx.DropTable(tireChangeBookingEntity{}.TableName()).Error
+		},
+	}
+}
+
+// addServiceTypes introduces service catalogue, existing bookings are considered to be single tire change services
+var addServiceTypes = &gormigrate.Migration{
+	ID: "202610191101",
+
+	Migrate: func(db *gorm.DB) error {
+		type serviceTypeEntityVersion1 struct {
+			ID   uint   `gorm:"primary_key"`
+			Code string `gorm:"unique_index; not null"`
+
+			Name string
+
+			DurationMinutes uint
+
+			Price float64
+
+			CreatedAt time.Time
+			UpdatedAt time.Time
+		}
+
+		type tireChangeBookingEntityVersion2 struct {
+			StartTireChangeTimeID uint `gorm:"index"`
+
+			ServiceTypeCode string
+		}
+
+		err := db.Table(serviceTypeEntity{}.TableName()).CreateTable(&serviceTypeEntityVersion1{}).Error
+
+		for _, serviceType := range []*serviceTypeEntityVersion1{
+			{Code: defaultServiceTypeCode, Name: "Tire change", DurationMinutes: 60, Price: 35},
+			{Code: "WHEEL_BALANCING", Name: "Wheel balancing", DurationMinutes: 120, Price: 60},
+			{Code: "TIRE_HOTEL_PICKUP", Name: "Tire hotel pickup", DurationMinutes: 30, Price: 10},
+		} {
+			if err != nil {
+				break
+			}
+
+			serviceType.CreatedAt = time.Now()
+			serviceType.UpdatedAt = time.Now()
+			err = db.Table(serviceTypeEntity{}.TableName()).Create(serviceType).Error
+		}
+
+		if err == nil {
+			err = db.Table(tireChangeBookingEntity{}.TableName()).AutoMigrate(&tireChangeBookingEntityVersion2{}).Error
+		}
+
+		if err == nil {
+			err = db.Exec(
+				"UPDATE tire_change_booking SET start_tire_change_time_id = tire_change_time_id, service_type_code = ?",
+				defaultServiceTypeCode,
+			).Error
+		}
+
+		if err == nil {
+			log.Info("Migrated 202610191101")
+		}
+
+		return err
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.DropTable(serviceTypeEntity{}.TableName()).Error
+	},
+}
+
+// addBookingPrice stores agreed price with the booking, existing bookings are priced by their service base price
+var addBookingPrice = &gormigrate.Migration{
+	ID: "202610191201",
+
+	Migrate: func(db *gorm.DB) error {
+		type tireChangeBookingEntityVersion3 struct {
+			VehicleType string
+
+			Price float64
+		}
+
+		err := db.Table(tireChangeBookingEntity{}.TableName()).AutoMigrate(&tireChangeBookingEntityVersion3{}).Error
+
+		if err == nil {
+			err = db.Exec(
+				"UPDATE tire_change_booking SET vehicle_type = ?, "+
+					"price = (SELECT price FROM service_type WHERE service_type.code = tire_change_booking.service_type_code)",
+				defaultVehicleType,
+			).Error
+		}
+
+		if err == nil {
+			log.Info("Migrated 202610191201")
+		}
+
+		return err
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		// SQLite does not support dropping columns, remaining columns are ignored by previous versions
+		return nil
+	},
+}
+
+// addTireHotel introduces tire sets stored by the workshop, which can be attached to bookings
+var addTireHotel = &gormigrate.Migration{
+	ID: "202610191301",
+
+	Migrate: func(db *gorm.DB) error {
+		type tireSetEntityVersion1 struct {
+			ID uint `gorm:"primary_key"`
+
+			ContactInformation string `gorm:"index"`
+
+			Size      string
+			Brand     string
+			Condition string
+
+			StorageLocation string
+
+			CreatedAt time.Time
+			UpdatedAt time.Time
+		}
+
+		type tireChangeBookingEntityVersion4 struct {
+			TireSetID uint
+		}
+
+		err := db.Table(tireSetEntity{}.TableName()).CreateTable(&tireSetEntityVersion1{}).Error
+
+		if err == nil {
+			err = db.Table(tireChangeBookingEntity{}.TableName()).AutoMigrate(&tireChangeBookingEntityVersion4{}).Error
+		}
+
+		if err == nil {
+			log.Info("Migrated 202610191301")
+		}
+
+		return err
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.DropTable(tireSetEntity{}.TableName()).Error
+	},
+}
+
+// addSlotVehicleTypes allows restricting bays to vehicle types they can lift, existing bays service all vehicle types
+var addSlotVehicleTypes = &gormigrate.Migration{
+	ID: "202610191401",
+
+	Migrate: func(db *gorm.DB) error {
+		type tireChangeTimeEntityVersion3 struct {
+			VehicleTypes string
+		}
+
+		err := db.Table(tireChangeTimeEntity{}.TableName()).AutoMigrate(&tireChangeTimeEntityVersion3{}).Error
+
+		if err == nil {
+			err = db.Exec("UPDATE tire_change_time SET vehicle_types = ''").Error
+		}
+
+		if err == nil {
+			log.Info("Migrated 202610191401")
+		}
+
+		return err
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		// SQLite does not support dropping columns, remaining columns are ignored by previous versions
+		return nil
+	},
+}
+
+// addMechanics introduces workshop staff, every existing bay is operated by a mechanic working on weekdays
+// during opening hours
+func addMechanics(baysPerTimeSlot uint) *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610191501",
+
+		Migrate: func(db *gorm.DB) error {
+			type mechanicEntityVersion1 struct {
+				ID uint `gorm:"primary_key"`
+
+				Name string
+
+				CreatedAt time.Time
+				UpdatedAt time.Time
+			}
+
+			type mechanicShiftEntityVersion1 struct {
+				ID uint `gorm:"primary_key"`
+
+				MechanicID uint `gorm:"index"`
+
+				Weekday   time.Weekday
+				StartHour uint
+				EndHour   uint
+
+				CreatedAt time.Time
+				UpdatedAt time.Time
+			}
+
+			type mechanicAbsenceEntityVersion1 struct {
+				ID uint `gorm:"primary_key"`
+
+				MechanicID uint `gorm:"index"`
+
+				Date time.Time
+				Type string
+
+				CreatedAt time.Time
+				UpdatedAt time.Time
+			}
+
+			type tireChangeBookingEntityVersion5 struct {
+				RescheduleRequired bool
+			}
+
+			err := db.Table(mechanicEntity{}.TableName()).CreateTable(&mechanicEntityVersion1{}).Error
+
+			if err == nil {
+				err = db.Table(mechanicShiftEntity{}.TableName()).CreateTable(&mechanicShiftEntityVersion1{}).Error
+			}
+
+			if err == nil {
+				err = db.Table(mechanicAbsenceEntity{}.TableName()).CreateTable(&mechanicAbsenceEntityVersion1{}).Error
+			}
+
+			for i := uint(1); err == nil && i <= baysPerTimeSlot; i++ {
+				mechanic := &mechanicEntityVersion1{
+					Name:      fmt.Sprintf("Mechanic %d", i),
+					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
+				}
+				err = db.Table(mechanicEntity{}.TableName()).Create(mechanic).Error
+
+				for weekday := time.Monday; err == nil && weekday <= time.Friday; weekday++ {
+					err = db.Table(mechanicShiftEntity{}.TableName()).Create(&mechanicShiftEntityVersion1{
+						MechanicID: mechanic.ID,
+						Weekday:    weekday,
+						StartHour:  8,
+						EndHour:    17,
+						CreatedAt:  time.Now(),
+						UpdatedAt:  time.Now(),
+					}).Error
+				}
+			}
+
+			if err == nil {
+				err = db.Table(tireChangeBookingEntity{}.TableName()).AutoMigrate(&tireChangeBookingEntityVersion5{}).Error
+			}
+
+			if err == nil {
+				log.Info("Migrated 202610191501")
+			}
+
+			return err
+		},
+
+		Rollback: func(tx *gorm.DB) error {
+			err := tx.DropTable(mechanicAbsenceEntity{}.TableName()).Error
+
+			if err == nil {
+				err = tx.DropTable(mechanicShiftEntity{}.TableName()).Error
+			}
+
+			if err == nil {
+				err = tx.DropTable(mechanicEntity{}.TableName()).Error
+			}
+
+			return err
+		},
+	}
+}
+
+// addAvailabilityIndex indexes tire change times by availability and time to look up earliest available ones
+var addAvailabilityIndex = &gormigrate.Migration{
+	ID: "202610191601",
+
+	Migrate: func(db *gorm.DB) error {
+		err := db.Table(tireChangeTimeEntity{}.TableName()).
+			AddIndex("idx_tire_change_time_available_time", "available", "time").Error
+
+		if err == nil {
+			log.Info("Migrated 202610191601")
+		}
+
+		return err
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Table(tireChangeTimeEntity{}.TableName()).RemoveIndex("idx_tire_change_time_available_time").Error
+	},
+}
+
+// addPricesInPennies stores prices as whole pennies, decimal prices are kept in their former columns
+var addPricesInPennies = &gormigrate.Migration{
+	ID: "202610191701",
+
+	Migrate: func(db *gorm.DB) error {
+		type serviceTypeEntityVersion2 struct {
+			PricePennies int64
+		}
+
+		type tireChangeBookingEntityVersion6 struct {
+			PricePennies int64
+		}
+
+		err := db.Table(serviceTypeEntity{}.TableName()).AutoMigrate(&serviceTypeEntityVersion2{}).Error
+
+		if err == nil {
+			err = db.Table(tireChangeBookingEntity{}.TableName()).AutoMigrate(&tireChangeBookingEntityVersion6{}).Error
+		}
+
+		if err == nil {
+			err = db.Exec("UPDATE service_type SET price_pennies = CAST(ROUND(price * 100) AS INTEGER)").Error
+		}
+
+		if err == nil {
+			err = db.Exec("UPDATE tire_change_booking SET price_pennies = CAST(ROUND(price * 100) AS INTEGER)").Error
+		}
+
+		if err == nil {
+			log.Info("Migrated 202610191701")
+		}
+
+		return err
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		// SQLite does not support dropping columns, remaining columns are ignored by previous versions
+		return nil
+	},
+}
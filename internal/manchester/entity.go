@@ -1,9 +1,15 @@
 package manchester
 
 import (
+	"github.com/surmus/tire-change-workshop/internal/shared"
+	"sort"
+	"strings"
 	"time"
 )
 
+// tireChangeTimeDuration is the length of single tire change time, longer services reserve consecutive times
+const tireChangeTimeDuration = time.Hour
+
 var zeroTireChangeTimeEntity = &tireChangeTimeEntity{}
 
 type tireChangeTimeEntity struct {
@@ -13,29 +19,167 @@ type tireChangeTimeEntity struct {
 
 	Available bool
 
-	BookedByContact string
+	Capacity    uint
+	BookedCount uint
+
+	// VehicleTypes lists vehicle types the bay is able to service delimited by commas on both ends, e.g. ",CAR,SUV,",
+	// so single type is matched exactly. Empty value allows all types
+	VehicleTypes string
+
+	Bookings []*tireChangeBookingEntity `gorm:"foreignkey:TireChangeTimeID"`
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// newTireChangeTimeEntity creates single bay tire change time, unavailable time is considered booked outside the API
 func newTireChangeTimeEntity(changeTime time.Time, available bool) *tireChangeTimeEntity {
-	return &tireChangeTimeEntity{
+	entity := &tireChangeTimeEntity{
 		Time:      changeTime,
 		Available: available,
+		Capacity:  1,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+
+	if !available {
+		entity.BookedCount = entity.Capacity
+	}
+
+	return entity
 }
 
-func (e *tireChangeTimeEntity) makeBooking(contactInformation string) error {
-	if e == zeroTireChangeTimeEntity || !e.Available {
+// makeBooking takes place in tire change time for given booking, repeated booking follows booking policy
+func (e *tireChangeTimeEntity) makeBooking(booking *tireChangeBookingEntity, policy BookingPolicy) error {
+	if e == zeroTireChangeTimeEntity {
+		return newUnAvailableBookingError(e)
+	}
+
+	if e.bookingOf(booking.ContactInformation) != nil {
+		if policy == IdempotentBookingPolicy {
+			return nil
+		}
+
+		return newUnAvailableBookingError(e)
+	}
+
+	if !e.Available {
 		return newUnAvailableBookingError(e)
 	}
 
-	e.Available = false
+	e.Bookings = append(e.Bookings, booking)
+	e.BookedCount++
+	e.Available = e.BookedCount < e.Capacity
 	e.UpdatedAt = time.Now()
-	e.BookedByContact = contactInformation
+
+	return nil
+}
+
+// cancelBooking releases place booked by given contact, returns removed booking to be deleted
+func (e *tireChangeTimeEntity) cancelBooking(contactInformation string) (*tireChangeBookingEntity, error) {
+	booking := e.bookingOf(contactInformation)
+
+	if e == zeroTireChangeTimeEntity || booking == nil {
+		return nil, newInvalidBookingCancellationError(e)
+	}
+
+	for i, b := range e.Bookings {
+		if b == booking {
+			e.Bookings = append(e.Bookings[:i], e.Bookings[i+1:]...)
+			break
+		}
+	}
+
+	e.BookedCount--
+	e.Available = e.BookedCount < e.Capacity
+	e.UpdatedAt = time.Now()
+
+	return booking, nil
+}
+
+// staff sets the amount of bays on duty, flagging latest bookings over capacity for rescheduling
+func (e *tireChangeTimeEntity) staff(capacity uint) bool {
+	changed := e.Capacity != capacity
+	e.Capacity = capacity
+	e.Available = e.BookedCount < e.Capacity
+
+	bookings := append([]*tireChangeBookingEntity{}, e.Bookings...)
+	sort.Slice(bookings, func(i, j int) bool { return bookings[i].ID < bookings[j].ID })
+
+	external := e.BookedCount - uint(len(bookings))
+	fitting := uint(0)
+
+	if capacity > external {
+		fitting = capacity - external
+	}
+
+	for i, booking := range bookings {
+		if rescheduleRequired := uint(i) >= fitting; booking.RescheduleRequired != rescheduleRequired {
+			booking.RescheduleRequired = rescheduleRequired
+			booking.UpdatedAt = time.Now()
+			changed = true
+		}
+	}
+
+	if changed {
+		e.UpdatedAt = time.Now()
+	}
+
+	return changed
+}
+
+// bookingOf returns booking held by given contact, requires loaded Bookings
+func (e *tireChangeTimeEntity) bookingOf(contactInformation string) *tireChangeBookingEntity {
+	for _, booking := range e.Bookings {
+		if booking.ContactInformation == contactInformation {
+			return booking
+		}
+	}
+
+	return nil
+}
+
+// supports tells whether the bay of tire change time is able to service given vehicle type
+func (e *tireChangeTimeEntity) supports(vehicleType string) bool {
+	for _, supported := range e.supportedVehicleTypes() {
+		if supported == vehicleType {
+			return true
+		}
+	}
+
+	return e.VehicleTypes == ""
+}
+
+// supportedVehicleTypes returns vehicle types the bay is restricted to, nil when all types are serviced
+func (e *tireChangeTimeEntity) supportedVehicleTypes() []string {
+	if e.VehicleTypes == "" {
+		return nil
+	}
+
+	return strings.Split(strings.Trim(e.VehicleTypes, ","), ",")
+}
+
+func (e *tireChangeTimeEntity) remainingCapacity() uint {
+	if e.BookedCount >= e.Capacity {
+		return 0
+	}
+
+	return e.Capacity - e.BookedCount
+}
+
+// checkBookingWindow verifies that tire change time fits into workshop booking window
+func (e *tireChangeTimeEntity) checkBookingWindow(now time.Time, minLeadTime time.Duration, maxAdvance time.Duration) error {
+	if e.Time.Before(now) {
+		return newPastBookingError(e)
+	}
+
+	if e.Time.Before(now.Add(minLeadTime)) {
+		return newBookingLeadTimeError(e, minLeadTime)
+	}
+
+	if maxAdvance > 0 && e.Time.After(now.Add(maxAdvance)) {
+		return newBookingAdvanceWindowError(e, maxAdvance)
+	}
 
 	return nil
 }
@@ -43,3 +187,348 @@ func (e *tireChangeTimeEntity) makeBooking(contactInformation string) error {
 func (e tireChangeTimeEntity) TableName() string {
 	return "tire_change_time"
 }
+
+// fittingStartTimes filters available tire change times followed by enough consecutive ones for the service
+func fittingStartTimes(available []*tireChangeTimeEntity, serviceType *serviceTypeEntity) []*tireChangeTimeEntity {
+	results := make([]*tireChangeTimeEntity, 0)
+	required := serviceType.requiredTireChangeTimes()
+
+	for i := range available {
+		consecutive := 1
+
+		for j := i + 1; j < len(available) && consecutive < required; j++ {
+			next := available[i].Time.Add(time.Duration(consecutive) * tireChangeTimeDuration)
+
+			if available[j].Time.After(next) {
+				break
+			} else if available[j].Time.Equal(next) {
+				consecutive++
+			}
+		}
+
+		if consecutive == required {
+			results = append(results, available[i])
+		}
+	}
+
+	return results
+}
+
+type tireChangeBookingEntity struct {
+	ID uint `gorm:"primary_key"`
+
+	TireChangeTimeID uint `gorm:"index"`
+
+	// StartTireChangeTimeID refers to the first of consecutive tire change times booked for the service
+	StartTireChangeTimeID uint `gorm:"index"`
+
+	ContactInformation string `gorm:"index"`
+
+	ServiceTypeCode string
+	VehicleType     string
+
+	// PricePennies is agreed price of the whole service in pennies, repeated on every tire change time it reserves
+	PricePennies int64
+
+	// TireSetID refers to stored tire set to be fetched from tire hotel for the service
+	TireSetID uint
+
+	// RescheduleRequired marks booking left without mechanic on duty, the contact has to be offered another time
+	RescheduleRequired bool
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func newTireChangeBookingEntity(
+	start *tireChangeTimeEntity,
+	contactInformation string,
+	serviceTypeCode string,
+	vehicleType string,
+	pricePennies int64,
+) *tireChangeBookingEntity {
+	return &tireChangeBookingEntity{
+		StartTireChangeTimeID: start.ID,
+		ContactInformation:    contactInformation,
+		ServiceTypeCode:       serviceTypeCode,
+		VehicleType:           vehicleType,
+		PricePennies:          pricePennies,
+		CreatedAt:             time.Now(),
+		UpdatedAt:             time.Now(),
+	}
+}
+
+// attachTireSet marks stored tire set to be fetched for the booked service
+func (e *tireChangeBookingEntity) attachTireSet(tireSet *tireSetEntity) {
+	e.TireSetID = tireSet.ID
+	e.UpdatedAt = time.Now()
+}
+
+func (e tireChangeBookingEntity) TableName() string {
+	return "tire_change_booking"
+}
+
+const (
+	defaultServiceTypeCode = "TIRE_CHANGE"
+	defaultVehicleType     = "CAR"
+)
+
+var zeroServiceTypeEntity = &serviceTypeEntity{}
+
+type serviceTypeEntity struct {
+	ID   uint   `gorm:"primary_key"`
+	Code string `gorm:"unique_index; not null"`
+
+	Name string
+
+	DurationMinutes uint
+
+	PricePennies int64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (e *serviceTypeEntity) duration() time.Duration {
+	return time.Duration(e.DurationMinutes) * time.Minute
+}
+
+// requiredTireChangeTimes returns the amount of consecutive tire change times needed to perform the service
+func (e *serviceTypeEntity) requiredTireChangeTimes() int {
+	required := int((e.duration() + tireChangeTimeDuration - 1) / tireChangeTimeDuration)
+
+	if required < 1 {
+		return 1
+	}
+
+	return required
+}
+
+func (e serviceTypeEntity) TableName() string {
+	return "service_type"
+}
+
+const (
+	waitlistStatusWaiting  = "WAITING"
+	waitlistStatusAssigned = "ASSIGNED"
+)
+
+var zeroWaitlistEntryEntity = &waitlistEntryEntity{}
+
+type waitlistEntryEntity struct {
+	ID uint `gorm:"primary_key"`
+
+	ContactInformation string
+
+	TireChangeTimeID uint
+	Date             time.Time
+
+	Status string `gorm:"index"`
+
+	AssignedTireChangeTimeID uint
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func newWaitlistEntryEntity(contactInformation string, tireChangeTimeID uint, date time.Time) *waitlistEntryEntity {
+	return &waitlistEntryEntity{
+		ContactInformation: contactInformation,
+		TireChangeTimeID:   tireChangeTimeID,
+		Date:               date,
+		Status:             waitlistStatusWaiting,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+}
+
+// assign marks waitlist entry as fulfilled by given tire change time, which must already be booked for entry contact
+func (e *waitlistEntryEntity) assign(tireChangeTime *tireChangeTimeEntity) {
+	e.Status = waitlistStatusAssigned
+	e.AssignedTireChangeTimeID = tireChangeTime.ID
+	e.UpdatedAt = time.Now()
+}
+
+func (e waitlistEntryEntity) TableName() string {
+	return "waitlist_entry"
+}
+
+var zeroTireSetEntity = &tireSetEntity{}
+
+// tireSetEntity is customers off-season tire set stored in workshop tire hotel
+type tireSetEntity struct {
+	ID uint `gorm:"primary_key"`
+
+	ContactInformation string `gorm:"index"`
+
+	Size      string
+	Brand     string
+	Condition string
+
+	StorageLocation string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func newTireSetEntity(
+	contactInformation string,
+	size string,
+	brand string,
+	condition string,
+	storageLocation string,
+) *tireSetEntity {
+	return &tireSetEntity{
+		ContactInformation: contactInformation,
+		Size:               size,
+		Brand:              brand,
+		Condition:          condition,
+		StorageLocation:    storageLocation,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+}
+
+func (e tireSetEntity) TableName() string {
+	return "tire_set"
+}
+
+const (
+	absenceTypeDayOff    = "DAY_OFF"
+	absenceTypeSickLeave = "SICK_LEAVE"
+)
+
+var zeroMechanicEntity = &mechanicEntity{}
+
+// mechanicEntity is workshop employee performing the services, every mechanic on duty operates single bay
+type mechanicEntity struct {
+	ID uint `gorm:"primary_key"`
+
+	Name string
+
+	Shifts   []*mechanicShiftEntity   `gorm:"foreignkey:MechanicID"`
+	Absences []*mechanicAbsenceEntity `gorm:"foreignkey:MechanicID"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func newMechanicEntity(name string) *mechanicEntity {
+	return &mechanicEntity{
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// onDuty tells whether mechanic works during the hour of given time, absences override weekly shifts
+func (e *mechanicEntity) onDuty(t time.Time) bool {
+	for _, absence := range e.Absences {
+		if absence.covers(t) {
+			return false
+		}
+	}
+
+	for _, shift := range e.Shifts {
+		if shift.covers(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// absenceOf returns absence of the mechanic with given ID, requires loaded Absences
+func (e *mechanicEntity) absenceOf(id uint) *mechanicAbsenceEntity {
+	for _, absence := range e.Absences {
+		if absence.ID == id {
+			return absence
+		}
+	}
+
+	return nil
+}
+
+func (e mechanicEntity) TableName() string {
+	return "mechanic"
+}
+
+// mechanicShiftEntity is weekly recurring working time of the mechanic, in whole hours of workshop local time
+type mechanicShiftEntity struct {
+	ID uint `gorm:"primary_key"`
+
+	MechanicID uint `gorm:"index"`
+
+	Weekday   time.Weekday
+	StartHour uint
+	EndHour   uint
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func newMechanicShiftEntity(
+	mechanic *mechanicEntity,
+	weekday time.Weekday,
+	startHour uint,
+	endHour uint,
+) *mechanicShiftEntity {
+	return &mechanicShiftEntity{
+		MechanicID: mechanic.ID,
+		Weekday:    weekday,
+		StartHour:  startHour,
+		EndHour:    endHour,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+}
+
+// covers tells whether shift includes given time, shift hours are in server time zone
+func (e *mechanicShiftEntity) covers(t time.Time) bool {
+	t = shared.InServerTimeZone(t)
+
+	return t.Weekday() == e.Weekday && uint(t.Hour()) >= e.StartHour && uint(t.Hour()) < e.EndHour
+}
+
+func (e mechanicShiftEntity) TableName() string {
+	return "mechanic_shift"
+}
+
+// mechanicAbsenceEntity is whole day the mechanic does not work regardless of shifts, either planned or sick leave
+type mechanicAbsenceEntity struct {
+	ID uint `gorm:"primary_key"`
+
+	MechanicID uint `gorm:"index"`
+
+	Date time.Time
+	Type string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func newMechanicAbsenceEntity(mechanic *mechanicEntity, date time.Time, absenceType string) *mechanicAbsenceEntity {
+	return &mechanicAbsenceEntity{
+		MechanicID: mechanic.ID,
+		Date:       date,
+		Type:       absenceType,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+}
+
+func (e *mechanicAbsenceEntity) covers(t time.Time) bool {
+	return !t.Before(e.Date) && t.Before(e.Date.AddDate(0, 0, 1))
+}
+
+func (e mechanicAbsenceEntity) TableName() string {
+	return "mechanic_absence"
+}
+
+// dailyAvailability aggregates tire change times of single day
+type dailyAvailability struct {
+	Date      string
+	Total     uint
+	Available uint
+	Booked    uint
+}
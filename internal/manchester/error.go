@@ -1,23 +1,53 @@
 package manchester
 
-import "fmt"
+import (
+	"fmt"
+	"github.com/surmus/tire-change-workshop/internal/shared"
+	"net/http"
+	"time"
+)
 
 const (
-	validationErrorCode      = "11"
-	unAvailableTimeErrorCode = "22"
+	validationErrorCode             = "11"
+	unknownServiceTypeErrorCode     = "12"
+	unknownVehicleTypeErrorCode     = "13"
+	unsupportedVehicleErrorCode     = "21"
+	unAvailableTimeErrorCode        = "22"
+	serviceDoesNotFitErrorCode      = "28"
+	invalidTireSetErrorCode         = "29"
+	overlappingBookingErrorCode     = "30"
+	notBookedByContactErrorCode     = "20"
+	invalidCancellationErrorCode    = "23"
+	bookingLimitErrorCode           = "24"
+	pastBookingErrorCode            = "25"
+	bookingLeadTimeErrorCode        = "26"
+	bookingAdvanceErrorCode         = "27"
+	unknownWaitlistEntryErrorCode   = "31"
+	unknownTireSetErrorCode         = "32"
+	unknownMechanicErrorCode        = "33"
+	unknownBranchErrorCode          = "34"
+	unknownTireChangeTimeErrorCode  = "35"
+	unknownMechanicAbsenceErrorCode = "36"
 )
 
 type tireChangeApplicationError struct {
 	code  string
 	error string
+	cause error
+	// alternatives suggests tire change times to book instead of unavailable one
+	alternatives []*tireChangeTimeBookingResponse
 }
 
 func (e tireChangeApplicationError) Error() string {
 	return e.error
 }
 
+func (e tireChangeApplicationError) Unwrap() error {
+	return e.cause
+}
+
 func newValidationError(cause error) *tireChangeApplicationError {
-	return &tireChangeApplicationError{code: validationErrorCode, error: cause.Error()}
+	return &tireChangeApplicationError{code: validationErrorCode, error: cause.Error(), cause: cause}
 }
 
 func newUnAvailableBookingError(e *tireChangeTimeEntity) *tireChangeApplicationError {
@@ -25,3 +55,154 @@ func newUnAvailableBookingError(e *tireChangeTimeEntity) *tireChangeApplicationE
 		code:  unAvailableTimeErrorCode,
 		error: fmt.Sprintf("tire change time %d is unavailable", e.ID)}
 }
+
+func newUnknownServiceTypeError(code string) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  unknownServiceTypeErrorCode,
+		error: fmt.Sprintf("service type %s does not exist", code)}
+}
+
+func newUnknownVehicleTypeError(vehicleType string) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  unknownVehicleTypeErrorCode,
+		error: fmt.Sprintf("vehicle type %s is not serviced", vehicleType)}
+}
+
+func newServiceDoesNotFitError(e *tireChangeTimeEntity, serviceType *serviceTypeEntity) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code: serviceDoesNotFitErrorCode,
+		error: fmt.Sprintf(
+			"tire change time %d is not followed by enough available tire change times for %s",
+			e.ID,
+			serviceType.Name,
+		)}
+}
+
+func newOverlappingBookingError(e *tireChangeTimeEntity) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  overlappingBookingErrorCode,
+		error: fmt.Sprintf("tire change time %d is already booked by the contact for another service", e.ID)}
+}
+
+func newNotBookedByContactError(e *tireChangeTimeEntity) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  notBookedByContactErrorCode,
+		error: fmt.Sprintf("tire change time %d is not booked by given contact", e.ID)}
+}
+
+func newInvalidBookingCancellationError(e *tireChangeTimeEntity) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  invalidCancellationErrorCode,
+		error: fmt.Sprintf("tire change time %d is not booked by given contact", e.ID)}
+}
+
+func newUnknownWaitlistEntryError(id uint) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  unknownWaitlistEntryErrorCode,
+		error: fmt.Sprintf("waitlist entry %d does not exist", id)}
+}
+
+func newBookingLimitExceededError(contactInformation string, limit uint) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  bookingLimitErrorCode,
+		error: fmt.Sprintf("contact %s has reached the limit of %d active bookings", contactInformation, limit)}
+}
+
+func newPastBookingError(e *tireChangeTimeEntity) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  pastBookingErrorCode,
+		error: fmt.Sprintf("tire change time %d has already passed", e.ID)}
+}
+
+func newBookingLeadTimeError(e *tireChangeTimeEntity, minLeadTime time.Duration) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  bookingLeadTimeErrorCode,
+		error: fmt.Sprintf("tire change time %d must be booked at least %s in advance", e.ID, minLeadTime)}
+}
+
+func newBookingAdvanceWindowError(e *tireChangeTimeEntity, maxAdvance time.Duration) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  bookingAdvanceErrorCode,
+		error: fmt.Sprintf("tire change time %d cannot be booked more than %s in advance", e.ID, maxAdvance)}
+}
+
+func newUnknownTireChangeTimeError(id uint) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  unknownTireChangeTimeErrorCode,
+		error: fmt.Sprintf("tire change time %d does not exist", id)}
+}
+
+func newUnknownTireSetError(id uint) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  unknownTireSetErrorCode,
+		error: fmt.Sprintf("tire set %d does not exist", id)}
+}
+
+func newInvalidTireSetError(id uint) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  invalidTireSetErrorCode,
+		error: fmt.Sprintf("tire set %d is not stored for given contact", id)}
+}
+
+func newUnsupportedVehicleTypeError(e *tireChangeTimeEntity, vehicleType string) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  unsupportedVehicleErrorCode,
+		error: fmt.Sprintf("tire change time %d bay is not able to service vehicle type %s", e.ID, vehicleType)}
+}
+
+func newUnknownMechanicError(id uint) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  unknownMechanicErrorCode,
+		error: fmt.Sprintf("mechanic %d does not exist", id)}
+}
+
+func newUnknownMechanicAbsenceError(mechanic *mechanicEntity, id uint) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  unknownMechanicAbsenceErrorCode,
+		error: fmt.Sprintf("absence %d of mechanic %d does not exist", id, mechanic.ID)}
+}
+
+func newUnknownBranchError(id string) *tireChangeApplicationError {
+	return &tireChangeApplicationError{
+		code:  unknownBranchErrorCode,
+		error: fmt.Sprintf("branch %s does not exist", id)}
+}
+
+type problemType struct {
+	name  string
+	title string
+}
+
+// problemTypes maps error codes to RFC 7807 problem types
+var problemTypes = map[string]problemType{
+	validationErrorCode:             {"invalid-request", "Request is invalid"},
+	unknownServiceTypeErrorCode:     {"unknown-service-type", "Service type is unknown"},
+	unknownVehicleTypeErrorCode:     {"unknown-vehicle-type", "Vehicle type is unknown"},
+	unsupportedVehicleErrorCode:     {"unsupported-vehicle-type", "Vehicle type is not supported by the bay"},
+	unAvailableTimeErrorCode:        {"unavailable-tire-change-time", "Tire change time is unavailable"},
+	serviceDoesNotFitErrorCode:      {"service-does-not-fit", "Service does not fit available tire change times"},
+	invalidTireSetErrorCode:         {"invalid-tire-set", "Tire set can not be used for the booking"},
+	overlappingBookingErrorCode:     {"overlapping-booking", "Tire change times are partly booked by the contact already"},
+	invalidCancellationErrorCode:    {"invalid-cancellation", "Booking can not be cancelled by the contact"},
+	notBookedByContactErrorCode:     {"not-booked-by-contact", "Tire change time is not booked by the contact"},
+	bookingLimitErrorCode:           {"booking-limit-exceeded", "Contact has reached the booking limit"},
+	pastBookingErrorCode:            {"outside-booking-window", "Tire change time is outside of booking window"},
+	bookingLeadTimeErrorCode:        {"outside-booking-window", "Tire change time is outside of booking window"},
+	bookingAdvanceErrorCode:         {"outside-booking-window", "Tire change time is outside of booking window"},
+	unknownWaitlistEntryErrorCode:   {"unknown-waitlist-entry", "Waitlist entry does not exist"},
+	unknownTireSetErrorCode:         {"unknown-tire-set", "Tire set does not exist"},
+	unknownTireChangeTimeErrorCode:  {"unknown-tire-change-time", "Tire change time does not exist"},
+	unknownMechanicErrorCode:        {"unknown-mechanic", "Mechanic does not exist"},
+	unknownMechanicAbsenceErrorCode: {"unknown-mechanic-absence", "Mechanic absence does not exist"},
+	unknownBranchErrorCode:          {"unknown-branch", "Branch does not exist"},
+}
+
+// problemTypeOf resolves RFC 7807 problem type URI and title of the error code, unexpected errors are of blank type
+// titled by the HTTP status
+func problemTypeOf(errorCode string) (string, string) {
+	if problemType, ok := problemTypes[errorCode]; ok {
+		return shared.ProblemTypeURNPrefix + problemType.name, problemType.title
+	}
+
+	return "about:blank", http.StatusText(http.StatusInternalServerError)
+}
@@ -1,6 +1,7 @@
 package manchester
 
 import (
+	"embed"
 	"github.com/gin-gonic/gin"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/sqlite" // initializes SQLite GORM dialect
@@ -13,17 +14,158 @@ import (
 
 var db *gorm.DB
 
-// Init initializes manchester application context by setting up database and registering REST endpoints,
-// returns Gin Router instance with registered endpoints
-func Init(debugMode bool) *gin.Engine {
-	db = initDB(debugMode)
-	repository := newTireChangeTimeRepository(db)
-	service := newTireChangeTimesService(repository)
+//go:embed openapi.yaml
+var openAPIDocument embed.FS
+
+// DefaultBranchID identifies the branch described by top level Config, the branch is also served by /api/v2 routes
+const DefaultBranchID = "main"
+
+// BookingPolicy defines how booking of already booked tire change time by the same contact is handled
+type BookingPolicy string
+
+const (
+	// StrictBookingPolicy rejects booking of every unavailable tire change time, including own bookings
+	StrictBookingPolicy BookingPolicy = "strict"
+	// IdempotentBookingPolicy treats repeated booking of tire change time by the same contact as successful no-op
+	IdempotentBookingPolicy BookingPolicy = "idempotent"
+)
+
+// Config contains manchester workshop booking rules, zero value disables all optional rules
+type Config struct {
+	// BaysPerTimeSlot is the amount of simultaneous tire changes per tire change time, 0 defaults to 1.
+	// Bays are used only while operated by mechanics on duty
+	BaysPerTimeSlot uint
+	// MaxActiveBookingsPerContact limits the amount of future bookings held by single contact, 0 means unlimited
+	MaxActiveBookingsPerContact uint
+	// MinBookingLeadTime is the minimum time between booking and tire change time, past times are never bookable
+	MinBookingLeadTime time.Duration
+	// MaxBookingAdvance limits how far in the future tire change times can be booked, 0 means unlimited
+	MaxBookingAdvance time.Duration
+	// BookingPolicy handles repeated bookings by the same contact, empty value defaults to StrictBookingPolicy
+	BookingPolicy BookingPolicy
+	// Workshop describes the workshop to API clients, empty fields default to DefaultWorkshopInfo values
+	Workshop WorkshopInfo
+	// Pricing is the price list of the branch, price list without vehicle types defaults to DefaultPricing
+	Pricing Pricing
+	// AdminAPI enables unauthenticated /admin routes managing mechanic shifts and absences of every branch, it
+	// should be enabled only behind access control. Admin routes are enabled for all branches by top level Config
+	AdminAPI bool
+	// BranchID identifies the branch in /branches/{branchId}/api/v2 routes, empty value defaults to DefaultBranchID
+	BranchID string
+	// Branches are further workshop branches hosted by the same server, each having separate schedule and bookings.
+	// Branches listed by a branch are ignored
+	Branches []Config
+}
+
+func (c Config) branchID() string {
+	if c.BranchID == "" {
+		return DefaultBranchID
+	}
+
+	return c.BranchID
+}
+
+// bays returns configured amount of bays per tire change time, defaulting to single bay
+func (c Config) bays() uint {
+	if c.BaysPerTimeSlot == 0 {
+		return 1
+	}
+
+	return c.BaysPerTimeSlot
+}
+
+// WorkshopInfo contains workshop details published by workshop information endpoint
+type WorkshopInfo struct {
+	Name      string
+	Address   string
+	Latitude  float64
+	Longitude float64
+	// OpeningHours uses OpenStreetMap opening_hours syntax, e.g. "Mo-Fr 08:00-17:00"
+	OpeningHours string
+}
+
+// DefaultWorkshopInfo describes manchester workshop unless configured otherwise
+var DefaultWorkshopInfo = WorkshopInfo{
+	Name:         "Manchester tire workshop",
+	Address:      "1 Tyre Lane, Manchester M1 1AA, United Kingdom",
+	Latitude:     53.4808,
+	Longitude:    -2.2426,
+	OpeningHours: "Mo-Fr 08:00-17:00",
+}
+
+// withDefaults fills empty fields with DefaultWorkshopInfo values, coordinates are replaced only together
+func (i WorkshopInfo) withDefaults() WorkshopInfo {
+	if i.Name == "" {
+		i.Name = DefaultWorkshopInfo.Name
+	}
+
+	if i.Address == "" {
+		i.Address = DefaultWorkshopInfo.Address
+	}
+
+	if i.Latitude == 0 && i.Longitude == 0 {
+		i.Latitude, i.Longitude = DefaultWorkshopInfo.Latitude, DefaultWorkshopInfo.Longitude
+	}
+
+	if i.OpeningHours == "" {
+		i.OpeningHours = DefaultWorkshopInfo.OpeningHours
+	}
+
+	return i
+}
+
+// Pricing is the price list of the workshop adjusting base prices of services, factors and surcharges are given in
+// percents of the base price. Surcharges apply by the tire change time in server time zone
+type Pricing struct {
+	// VehicleTypeFactors lists vehicle types serviced by the workshop with their percent of the base price, e.g. 120
+	VehicleTypeFactors map[string]int64
+	// PeakHours are hours of the day charged with PeakHourSurcharge
+	PeakHours         []int
+	PeakHourSurcharge int64
+	// PeakSeasonMonths are months charged with SeasonalSurcharge
+	PeakSeasonMonths  []time.Month
+	SeasonalSurcharge int64
+	// WeekendSurcharge is charged on Saturdays and Sundays
+	WeekendSurcharge int64
+}
+
+// DefaultPricing is manchester workshop price list unless configured otherwise
+var DefaultPricing = Pricing{
+	VehicleTypeFactors: map[string]int64{defaultVehicleType: 100, "SUV": 125, "VAN": 140},
+	PeakHours:          []int{8, 9, 16},
+	PeakHourSurcharge:  10,
+	PeakSeasonMonths:   []time.Month{time.April, time.October, time.November},
+	SeasonalSurcharge:  15,
+	WeekendSurcharge:   30,
+}
+
+// withDefaults replaces price list without vehicle types by DefaultPricing, other price lists are used as configured
+func (p Pricing) withDefaults() Pricing {
+	if len(p.VehicleTypeFactors) == 0 {
+		return DefaultPricing
+	}
+
+	return p
+}
+
+// Init initializes manchester application context by setting up database of every branch and registering REST
+// endpoints, returns Gin Router instance with registered endpoints
+func Init(debugMode bool, config Config) *gin.Engine {
+	db = initDB(debugMode, config)
+	defaultBranch := newBranch(db, config)
+	branches := map[string]*controller{config.branchID(): defaultBranch}
+
+	for _, branchConfig := range config.Branches {
+		branches[branchConfig.branchID()] = newBranch(initDB(debugMode, branchConfig), branchConfig)
+	}
 
 	if !debugMode {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	shared.UseRequestParameterNames()
+
+	document := shared.NewOpenAPIDocument(openAPIDocument, "openapi.yaml", v2Path, gin.MIMEJSON)
 	r := gin.New()
 	// Add a ginrus middleware, which:
 	//   - Logs all requests, like a combined access and errors log.
@@ -32,15 +174,45 @@ func Init(debugMode bool) *gin.Engine {
 	r.Use(shared.GinRusMiddleware(log.StandardLogger(), time.RFC3339, true))
 	// Recovery middleware recovers from any panics and writes a 500 if there was one.
 	r.Use(gin.Recovery())
-	// ErrorHandler middleware catches application errors and renders them as XML
+	// ConditionalGet middleware tags GET responses by ETag and answers unchanged ones with 304 Not Modified
+	r.Use(shared.ConditionalGetMiddleware("no-cache"))
+
+	if debugMode {
+		// ResponseValidation middleware replaces responses deviating from OpenAPI document by 500 Internal Server Error
+		r.Use(document.ResponseValidationMiddleware())
+	}
+
+	// ErrorHandler middleware catches application errors and renders them in negotiated format
 	r.Use(errorHandlerMiddleware())
+	// RequestValidation middleware rejects requests not conforming to OpenAPI document
+	r.Use(document.RequestValidationMiddleware(func(err error) error { return newValidationError(err) }))
 	// Register application routes
-	registerController(r, service)
+	registerController(r, defaultBranch, branches, config.AdminAPI)
+	r.GET("/openapi.yaml", document.ServeYAML)
+	r.GET("/openapi.json", document.ServeJSON)
 
 	return r
 }
 
-func initDB(debugMode bool) *gorm.DB {
+// newBranch sets up application services of single workshop branch using given database
+func newBranch(db *gorm.DB, config Config) *controller {
+	repository := newTireChangeTimeRepository(db)
+	serviceTypes := newServiceTypeRepository(db)
+	rules := newBookingRules(config)
+	pricing := newPricingEngine(config.Pricing.withDefaults())
+	waitlist := newWaitlistService(newWaitlistRepository(db), repository, serviceTypes, rules, pricing)
+	tireSets := newTireSetRepository(db)
+	service := newTireChangeTimesService(repository, serviceTypes, tireSets, waitlist, rules, pricing)
+	tireHotel := newTireHotelService(tireSets)
+	workshop := newWorkshopService(config.Workshop.withDefaults(), pricing)
+	staff := newStaffService(newMechanicRepository(db), repository, waitlist, config.bays())
+	// capacity of upcoming tire change times follows shifts of mechanics on duty from the start
+	staff.adjustCapacity(time.Now(), time.Time{})
+
+	return newController(service, waitlist, tireHotel, workshop, staff)
+}
+
+func initDB(debugMode bool, config Config) *gorm.DB {
 	db, err := gorm.Open("sqlite3", ":memory:")
 	db.DB().SetMaxOpenConns(1) // Fixes possible error occurring with concurrent requests
 
@@ -50,17 +222,28 @@ func initDB(debugMode bool) *gorm.DB {
 
 	db.LogMode(debugMode)
 
-	runDBMigration(db)
+	runDBMigration(db, config)
 
-	log.Info("Database initialized")
+	log.Infof("Database of branch %s initialized", config.branchID())
 
 	return db
 }
 
-func runDBMigration(db *gorm.DB) {
+func runDBMigration(db *gorm.DB, config Config) {
 	log.Info("DB migrations :: START")
 
-	m := gormigrate.New(db, gormigrate.DefaultOptions, []*gormigrate.Migration{initial})
+	m := gormigrate.New(db, gormigrate.DefaultOptions, []*gormigrate.Migration{
+		initial,
+		addWaitlist,
+		addCapacity(config.bays()),
+		addServiceTypes,
+		addBookingPrice,
+		addTireHotel,
+		addSlotVehicleTypes,
+		addMechanics(config.bays()),
+		addAvailabilityIndex,
+		addPricesInPennies,
+	})
 
 	if err := m.Migrate(); err != nil {
 		log.Fatalf("Could not migrate: %v", err)
@@ -3,11 +3,18 @@ package manchester
 import (
 	"bytes"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/stretchr/testify/assert"
+	"github.com/surmus/tire-change-workshop/internal/shared"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -15,7 +22,7 @@ import (
 const rfc3339DateFormat = "2006-01-02"
 
 func TestGetTireChangeTimes(t *testing.T) {
-	router := Init(true)
+	router := Init(true, Config{})
 
 	t.Run("successfully get all in correct order", func(t *testing.T) {
 		reqURL := v2Path + "/tire-change-times"
@@ -63,6 +70,67 @@ func TestGetTireChangeTimes(t *testing.T) {
 		verifyTireChangeTimesResponse(t, *result)
 	})
 
+	t.Run("successfully get available in a week in correct order", func(t *testing.T) {
+		from := time.Now().AddDate(0, 0, 1)
+		until := from.AddDate(0, 0, 6)
+		end := time.Date(until.Year(), until.Month(), until.Day()+1, 0, 0, 0, 0, time.Local)
+		reqURL := fmt.Sprintf(
+			v2Path+"/tire-change-times?from=%s&until=%s&available=true",
+			from.Format(rfc3339DateFormat),
+			until.Format(rfc3339DateFormat),
+		)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		verifyTireChangeTimesResponse(t, *result)
+
+		for _, tireChangeTime := range *result {
+			assert.True(t, tireChangeTime.Available)
+			assert.True(t, tireChangeTime.Time.Before(end))
+		}
+	})
+
+	t.Run("successfully get booked only", func(t *testing.T) {
+		reqURL := v2Path + "/tire-change-times?available=false"
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		verifyTireChangeTimesResponse(t, *result)
+
+		for _, tireChangeTime := range *result {
+			assert.False(t, tireChangeTime.Available)
+		}
+	})
+
+	t.Run("fail to get all until date before from date", func(t *testing.T) {
+		today := time.Now().Format(rfc3339DateFormat)
+		yesterday := time.Now().AddDate(0, 0, -1).Format(rfc3339DateFormat)
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times?from=%s&until=%s", today, yesterday)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.Equal(t, validationErrorCode, result.Code)
+		assert.NotEmpty(t, result.Message)
+	})
+
 	t.Run("fail to get all from invalid date", func(t *testing.T) {
 		reqURL := v2Path + "/tire-change-times?from=INVALID"
 
@@ -78,8 +146,53 @@ func TestGetTireChangeTimes(t *testing.T) {
 		assert.NotEmpty(t, result.Message)
 	})
 
-	t.Run("fail to get subset with invalid offset", func(t *testing.T) {
-		reqURL := v2Path + "/tire-change-times?amount=1&page=0"
+	t.Run("successfully page through all by cursor links", func(t *testing.T) {
+		getPage := func(reqURL string) (*httptest.ResponseRecorder, tireChangeTimesResponse) {
+			requestWriter := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+			router.ServeHTTP(requestWriter, req)
+
+			result := tireChangeTimesResponse{}
+			unMarshal(t, requestWriter.Body.Bytes(), &result)
+
+			assert.Equal(t, http.StatusOK, requestWriter.Code)
+			assert.Equal(t, "1500", requestWriter.Header().Get("X-Total-Count"))
+
+			return requestWriter, result
+		}
+
+		requestWriter, firstPage := getPage(v2Path + "/tire-change-times?limit=600")
+		assert.Len(t, firstPage, 600)
+		assert.Empty(t, linkOf(requestWriter, "prev"))
+
+		requestWriter, secondPage := getPage(linkOf(requestWriter, "next"))
+		assert.Len(t, secondPage, 600)
+		assert.True(t, secondPage[0].Time.After(firstPage[599].Time))
+
+		requestWriter, lastPage := getPage(linkOf(requestWriter, "next"))
+		assert.Len(t, lastPage, 300)
+		assert.Empty(t, linkOf(requestWriter, "next"))
+		verifyTireChangeTimesResponse(t, lastPage)
+
+		_, secondPageAgain := getPage(linkOf(requestWriter, "prev"))
+		assert.Equal(t, secondPage, secondPageAgain)
+	})
+
+	t.Run("successfully get subset with pagination headers", func(t *testing.T) {
+		reqURL := v2Path + "/tire-change-times?amount=101&page=14"
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, "1500", requestWriter.Header().Get("X-Total-Count"))
+		assert.Contains(t, linkOf(requestWriter, "prev"), "limit=101")
+		assert.Empty(t, linkOf(requestWriter, "next"))
+	})
+
+	t.Run("fail to get subset with invalid cursor", func(t *testing.T) {
+		reqURL := v2Path + "/tire-change-times?limit=10&cursor=INVALID"
 
 		requestWriter := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
@@ -90,15 +203,368 @@ func TestGetTireChangeTimes(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
 		assert.Equal(t, validationErrorCode, result.Code)
-		assert.NotEmpty(t, result.Message)
+	})
+
+	t.Run("fail to get subset with both limit and amount", func(t *testing.T) {
+		reqURL := v2Path + "/tire-change-times?limit=10&amount=10&page=1"
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+	})
+
+	t.Run("successfully get first page", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/tire-change-times", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		all := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), all)
+
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, v2Path+"/tire-change-times?amount=2&page=0", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, (*all)[:2], *result)
+		assert.Empty(t, linkOf(requestWriter, "prev"))
+	})
+
+	t.Run("successfully get only Friday mornings", func(t *testing.T) {
+		for _, serviceType := range []string{"", "WHEEL_BALANCING"} {
+			reqURL := fmt.Sprintf(
+				v2Path+"/tire-change-times"+"?from=%s&until=%s&timeFrom=08:00&timeUntil=12:00&weekdays=FRIDAY&serviceType=%s",
+				time.Now().Format(rfc3339DateFormat),
+				time.Now().AddDate(0, 0, 14).Format(rfc3339DateFormat),
+				serviceType,
+			)
+
+			requestWriter := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+			router.ServeHTTP(requestWriter, req)
+
+			result := &tireChangeTimesResponse{}
+			unMarshal(t, requestWriter.Body.Bytes(), result)
+
+			assert.Equal(t, http.StatusOK, requestWriter.Code)
+			assert.NotEmpty(t, *result)
+
+			for _, tireChangeTime := range *result {
+				assert.Equal(t, time.Friday, tireChangeTime.Time.Local().Weekday())
+				assert.Less(t, tireChangeTime.Time.Local().Hour(), 12)
+			}
+		}
+	})
+
+	t.Run("successfully get tire change times of several weekdays", func(t *testing.T) {
+		reqURL := fmt.Sprintf(
+			v2Path+"/tire-change-times"+"?from=%s&until=%s&weekdays=MONDAY&weekdays=WEDNESDAY",
+			time.Now().Format(rfc3339DateFormat),
+			time.Now().AddDate(0, 0, 14).Format(rfc3339DateFormat),
+		)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotEmpty(t, *result)
+
+		for _, tireChangeTime := range *result {
+			assert.Contains(t, []time.Weekday{time.Monday, time.Wednesday}, tireChangeTime.Time.Local().Weekday())
+		}
+	})
+
+	t.Run("fail to get tire change times with invalid schedule", func(t *testing.T) {
+		for _, schedule := range []string{"timeFrom=8", "timeUntil=25:00", "weekdays=FRIDAYS"} {
+			reqURL := fmt.Sprintf(
+				v2Path+"/tire-change-times"+"?from=%s&until=%s&%s",
+				time.Now().Format(rfc3339DateFormat),
+				time.Now().AddDate(0, 0, 14).Format(rfc3339DateFormat),
+				schedule,
+			)
+
+			requestWriter := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+			router.ServeHTTP(requestWriter, req)
+
+			assert.Equal(t, http.StatusBadRequest, requestWriter.Code, schedule)
+		}
+	})
+
+}
+
+func TestGetTireChangeTime(t *testing.T) {
+	router := Init(true, Config{})
+
+	getTireChangeTime := func(reqURL string) *httptest.ResponseRecorder {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	t.Run("successfully get booked tire change time", func(t *testing.T) {
+		bookedTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(bookedTireChangeTime).Error)
+		bookTireChangeTime(t, bookedTireChangeTime, "TEST")
+
+		requestWriter := getTireChangeTime(fmt.Sprintf(v2Path+"/tire-change-times/%d", bookedTireChangeTime.ID))
+
+		result := &tireChangeTimeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, bookedTireChangeTime.ID, result.ID)
+		assert.Equal(t, bookedTireChangeTime.Time.UTC(), result.Time)
+		assert.False(t, result.Available)
+		assert.Equal(t, uint(0), result.RemainingCapacity)
+		assert.NotZero(t, result.Price)
+	})
+
+	t.Run("successfully get tire change time quoted for service and vehicle type", func(t *testing.T) {
+		availableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(availableTireChangeTime).Error)
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d", availableTireChangeTime.ID)
+
+		defaultQuote := &tireChangeTimeBookingResponse{}
+		unMarshal(t, getTireChangeTime(reqURL).Body.Bytes(), defaultQuote)
+
+		requestWriter := getTireChangeTime(reqURL + "?serviceType=WHEEL_BALANCING&vehicleType=VAN")
+
+		result := &tireChangeTimeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.True(t, result.Available)
+		assert.Greater(t, result.Price, defaultQuote.Price)
+	})
+
+	t.Run("fail to get unknown tire change time", func(t *testing.T) {
+		requestWriter := getTireChangeTime(v2Path + "/tire-change-times/999999")
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		assert.Equal(t, unknownTireChangeTimeErrorCode, result.Code)
+	})
+
+	t.Run("fail to get tire change time with unknown service type", func(t *testing.T) {
+		requestWriter := getTireChangeTime(v2Path + "/tire-change-times/1?serviceType=UNKNOWN")
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+	})
+}
+
+func TestNextAvailableTireChangeTimes(t *testing.T) {
+	useLocalTimeZone(t, time.FixedZone("UTC-5", -5*60*60))
+	router := Init(true, Config{})
+	after := time.Now().AddDate(20, 0, 0).Truncate(time.Hour)
+
+	for i, available := range []bool{true, false, true, true} {
+		must(t, db.Create(newTireChangeTimeEntity(after.Add(time.Duration(i+1)*time.Hour), available)).Error)
+	}
+
+	getNextAvailable := func(query string) *httptest.ResponseRecorder {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/tire-change-times/next-available?"+query, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	t.Run("successfully get earliest available tire change times after given time", func(t *testing.T) {
+		requestWriter := getNextAvailable("count=2&after=" + after.UTC().Format(time.RFC3339))
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, *result, 2)
+		assert.Equal(t, after.Add(time.Hour).UTC(), (*result)[0].Time)
+		assert.Equal(t, after.Add(3*time.Hour).UTC(), (*result)[1].Time)
+		assert.NotZero(t, (*result)[0].Price)
+	})
+
+	t.Run("successfully get single earliest available tire change time by default", func(t *testing.T) {
+		requestWriter := getNextAvailable("")
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, *result, 1)
+		assert.True(t, (*result)[0].Time.After(time.Now()))
+	})
+
+	t.Run("successfully skip passed tire change times", func(t *testing.T) {
+		passed := newTireChangeTimeEntity(time.Now().Add(-time.Minute), true)
+		must(t, db.Create(passed).Error)
+
+		requestWriter := getNextAvailable("count=50&after=2006-01-02T15:04:05Z")
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, *result, 50)
+
+		for _, tireChangeTime := range *result {
+			assert.NotEqual(t, passed.ID, tireChangeTime.ID)
+			assert.True(t, tireChangeTime.Time.After(time.Now()))
+		}
+	})
+
+	t.Run("fail to get next available tire change times with invalid count", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, getNextAvailable("count=0").Code)
+		assert.Equal(t, http.StatusBadRequest, getNextAvailable("count=51").Code)
+	})
+
+	t.Run("fail to get next available tire change times with unknown vehicle type", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, getNextAvailable("vehicleType=TRUCK").Code)
+	})
+}
+
+func TestDailyAvailability(t *testing.T) {
+	router := Init(true, Config{AdminAPI: true})
+	year := time.Now().Year() + 21
+	day := time.Date(year, time.March, 1, 0, 0, 0, 0, time.Local)
+
+	partiallyBooked := newTireChangeTimeEntity(day.Add(10*time.Hour), true)
+	partiallyBooked.Capacity = 2
+	partiallyBooked.BookedCount = 1
+	restricted := newTireChangeTimeEntity(day.AddDate(0, 0, 1).Add(9*time.Hour), true)
+	restricted.VehicleTypes = ",VAN,"
+
+	for _, tireChangeTime := range []*tireChangeTimeEntity{
+		newTireChangeTimeEntity(day.Add(9*time.Hour), true),
+		partiallyBooked,
+		newTireChangeTimeEntity(day.Add(11*time.Hour), false),
+		restricted,
+		newTireChangeTimeEntity(day.AddDate(0, 0, 2).Add(9*time.Hour), true),
+	} {
+		must(t, db.Create(tireChangeTime).Error)
+	}
+
+	getDailyAvailability := func(query string) *httptest.ResponseRecorder {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/tire-change-times/daily-availability?"+query, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	t.Run("successfully count tire change times by days of the period", func(t *testing.T) {
+		requestWriter := getDailyAvailability(fmt.Sprintf("from=%d-03-01&until=%d-03-02", year, year))
+
+		result := &dailyAvailabilityResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, []*dayAvailabilityResponse{
+			{Date: fmt.Sprintf("%d-03-01", year), Total: 3, Available: 2, Booked: 1},
+			{Date: fmt.Sprintf("%d-03-02", year), Total: 1, Available: 1, Booked: 0},
+		}, []*dayAvailabilityResponse(*result))
+	})
+
+	t.Run("successfully count only tire change times able to service vehicle type", func(t *testing.T) {
+		requestWriter := getDailyAvailability(fmt.Sprintf("from=%d-03-02&until=%d-03-03&vehicleType=SUV", year, year))
+
+		result := &dailyAvailabilityResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, *result, 1)
+		assert.Equal(t, fmt.Sprintf("%d-03-03", year), (*result)[0].Date)
+	})
+
+	t.Run("fail to count tire change times of invalid period", func(t *testing.T) {
+		requestWriter := getDailyAvailability(fmt.Sprintf("from=%d-03-02&until=%d-03-01", year, year))
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+	})
+
+	t.Run("successfully count tire change times closed by mechanic absence neither available nor booked", func(t *testing.T) {
+		absenceDay := time.Date(year, time.April, 1, 0, 0, 0, 0, time.Local)
+
+		for absenceDay.Weekday() == time.Saturday || absenceDay.Weekday() == time.Sunday {
+			absenceDay = absenceDay.AddDate(0, 0, 1)
+		}
+
+		must(t, db.Create(newTireChangeTimeEntity(absenceDay.Add(10*time.Hour), true)).Error)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/admin/mechanics", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		var mechanics mechanicsResponse
+		unMarshal(t, requestWriter.Body.Bytes(), &mechanics)
+
+		for _, mechanic := range mechanics {
+			reqURL := fmt.Sprintf(v2Path+"/admin/mechanics/%d/absences", mechanic.ID)
+			request := &mechanicAbsenceRequest{Date: absenceDay.Format(rfc3339DateFormat), Type: absenceTypeDayOff}
+			requestWriter = httptest.NewRecorder()
+			req, _ = http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+			router.ServeHTTP(requestWriter, req)
+			assert.Equal(t, http.StatusOK, requestWriter.Code)
+		}
+
+		date := absenceDay.Format(rfc3339DateFormat)
+		requestWriter = getDailyAvailability(fmt.Sprintf("from=%s&until=%s", date, date))
+
+		result := &dailyAvailabilityResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, []*dayAvailabilityResponse{
+			{Date: date, Total: 1, Available: 0, Booked: 0},
+		}, []*dayAvailabilityResponse(*result))
+	})
+
+	t.Run("successfully count passed tire change times of today only in total", func(t *testing.T) {
+		now := time.Now()
+		today := now.Format(rfc3339DateFormat)
+		getToday := func() *dayAvailabilityResponse {
+			result := &dailyAvailabilityResponse{}
+			unMarshal(t, getDailyAvailability(fmt.Sprintf("from=%s&until=%s", today, today)).Body.Bytes(), result)
+
+			if len(*result) > 0 {
+				return (*result)[0]
+			}
+
+			return &dayAvailabilityResponse{Date: today}
+		}
+
+		before := getToday()
+		passed := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+		must(t, db.Create(newTireChangeTimeEntity(passed, true)).Error)
+		after := getToday()
+
+		assert.Equal(t, before.Total+1, after.Total)
+		assert.Equal(t, before.Available, after.Available)
+		assert.Equal(t, before.Booked, after.Booked)
+	})
+
+	t.Run("fail to count tire change times without period", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, getDailyAvailability("").Code)
 	})
 }
 
 func TestTireChangeTimeBooking(t *testing.T) {
-	router := Init(true)
+	router := Init(true, Config{})
 
 	t.Run("successfully book available tire change time", func(t *testing.T) {
-		availableTireChangeTime := newTireChangeTimeEntity(time.Now(), true)
+		availableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
 		must(t, db.Create(availableTireChangeTime).Error)
 
 		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", availableTireChangeTime.ID)
@@ -118,7 +584,7 @@ func TestTireChangeTimeBooking(t *testing.T) {
 	})
 
 	t.Run("fail to book unavailable tire change time", func(t *testing.T) {
-		availableTireChangeTime := newTireChangeTimeEntity(time.Now(), false)
+		availableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), false)
 		must(t, db.Create(availableTireChangeTime).Error)
 
 		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", availableTireChangeTime.ID)
@@ -136,6 +602,77 @@ func TestTireChangeTimeBooking(t *testing.T) {
 		assert.NotEmpty(t, result.Message)
 	})
 
+	t.Run("fail to book unavailable tire change time suggesting nearest alternatives", func(t *testing.T) {
+		requested := time.Now().AddDate(22, 0, 0).Truncate(time.Hour)
+		unAvailableTireChangeTime := newTireChangeTimeEntity(requested, false)
+		vanOnlyTireChangeTime := newTireChangeTimeEntity(requested.Add(-time.Hour), true)
+		vanOnlyTireChangeTime.VehicleTypes = ",VAN,"
+
+		for _, tireChangeTime := range []*tireChangeTimeEntity{
+			newTireChangeTimeEntity(requested.Add(-4*time.Hour), true),
+			newTireChangeTimeEntity(requested.Add(-3*time.Hour), true),
+			newTireChangeTimeEntity(requested.Add(-2*time.Hour), true),
+			vanOnlyTireChangeTime,
+			unAvailableTireChangeTime,
+			newTireChangeTimeEntity(requested.Add(time.Hour), false),
+			newTireChangeTimeEntity(requested.Add(2*time.Hour), true),
+			newTireChangeTimeEntity(requested.Add(3*time.Hour), true),
+			newTireChangeTimeEntity(requested.Add(4*time.Hour), true),
+		} {
+			must(t, db.Create(tireChangeTime).Error)
+		}
+
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", unAvailableTireChangeTime.ID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Len(t, result.Alternatives, 4)
+
+		for i, offset := range []time.Duration{-3 * time.Hour, -2 * time.Hour, 2 * time.Hour, 3 * time.Hour} {
+			assert.Equal(t, requested.Add(offset).UTC(), result.Alternatives[i].Time)
+			assert.NotZero(t, result.Alternatives[i].Price)
+		}
+	})
+
+	t.Run("fail to book passed tire change time suggesting only upcoming alternatives", func(t *testing.T) {
+		requested := time.Now().AddDate(0, 0, -3)
+		passedTireChangeTime := newTireChangeTimeEntity(requested, false)
+
+		for _, tireChangeTime := range []*tireChangeTimeEntity{
+			passedTireChangeTime,
+			newTireChangeTimeEntity(requested.Add(time.Minute), true),
+			newTireChangeTimeEntity(requested.Add(2*time.Minute), true),
+			newTireChangeTimeEntity(time.Now().Add(time.Hour), true),
+			newTireChangeTimeEntity(time.Now().Add(2*time.Hour), true),
+		} {
+			must(t, db.Create(tireChangeTime).Error)
+		}
+
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", passedTireChangeTime.ID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Len(t, result.Alternatives, 2)
+
+		for _, alternative := range result.Alternatives {
+			assert.True(t, alternative.Time.After(time.Now()))
+		}
+	})
+
 	t.Run("fail to book unknown tire change time", func(t *testing.T) {
 		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", 34534523423)
 		request := &tireChangeBookingRequest{ContactInformation: "TEST"}
@@ -168,7 +705,7 @@ func TestTireChangeTimeBooking(t *testing.T) {
 	})
 
 	t.Run("fail to book with invalid request", func(t *testing.T) {
-		availableTireChangeTime := newTireChangeTimeEntity(time.Now(), true)
+		availableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
 		must(t, db.Create(availableTireChangeTime).Error)
 
 		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", availableTireChangeTime.ID)
@@ -187,10 +724,1577 @@ func TestTireChangeTimeBooking(t *testing.T) {
 	})
 }
 
-func getTireChangeTime(t *testing.T, id uint) *tireChangeTimeEntity {
-	var result tireChangeTimeEntity
+func TestTireChangeTimeBookingPolicy(t *testing.T) {
+	book := func(router http.Handler, tireChangeTime *tireChangeTimeEntity, contact string) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", tireChangeTime.ID)
+		request := &tireChangeBookingRequest{ContactInformation: contact}
 
-	if err := db.Model(tireChangeTimeEntity{}).Where("id = ?", id).Find(&result).Error; err != nil {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	t.Run("fail to rebook own tire change time with strict policy", func(t *testing.T) {
+		router := Init(true, Config{BookingPolicy: StrictBookingPolicy})
+		bookedTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(bookedTireChangeTime).Error)
+		bookTireChangeTime(t, bookedTireChangeTime, "TEST")
+
+		requestWriter := book(router, bookedTireChangeTime, "TEST")
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, unAvailableTimeErrorCode, result.Code)
+	})
+
+	t.Run("successfully rebook own tire change time with idempotent policy", func(t *testing.T) {
+		router := Init(true, Config{BookingPolicy: IdempotentBookingPolicy})
+		bookedTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(bookedTireChangeTime).Error)
+		bookTireChangeTime(t, bookedTireChangeTime, "TEST")
+
+		requestWriter := book(router, bookedTireChangeTime, "TEST")
+
+		result := &tireChangeTimeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, bookedTireChangeTime.ID, result.ID)
+		assert.False(t, result.Available)
+		assert.NotNil(t, getTireChangeTime(t, bookedTireChangeTime.ID).bookingOf("TEST"))
+	})
+
+	t.Run("fail to book tire change time of another contact with idempotent policy", func(t *testing.T) {
+		router := Init(true, Config{BookingPolicy: IdempotentBookingPolicy})
+		bookedTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(bookedTireChangeTime).Error)
+		bookTireChangeTime(t, bookedTireChangeTime, "some guy")
+
+		requestWriter := book(router, bookedTireChangeTime, "another guy")
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, unAvailableTimeErrorCode, result.Code)
+		assert.NotNil(t, getTireChangeTime(t, bookedTireChangeTime.ID).bookingOf("some guy"))
+	})
+}
+
+func TestTireChangeTimeCapacity(t *testing.T) {
+	router := Init(true, Config{BaysPerTimeSlot: 3})
+
+	book := func(tireChangeTime *tireChangeTimeEntity, contactInformation string) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", tireChangeTime.ID)
+		request := &tireChangeBookingRequest{ContactInformation: contactInformation}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	t.Run("successfully list seeded tire change times with configured capacity", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/tire-change-times", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+
+		for _, tireChangeTime := range *result {
+			if tireChangeTime.Available {
+				assert.Equal(t, uint(3), tireChangeTime.RemainingCapacity)
+			} else {
+				assert.Equal(t, uint(0), tireChangeTime.RemainingCapacity)
+			}
+		}
+	})
+
+	t.Run("successfully book tire change time until its capacity is used up", func(t *testing.T) {
+		tireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		tireChangeTime.Capacity = 2
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "first")
+		result := &tireChangeTimeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.True(t, result.Available)
+		assert.Equal(t, uint(1), result.RemainingCapacity)
+
+		requestWriter = book(tireChangeTime, "second")
+		result = &tireChangeTimeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.False(t, result.Available)
+		assert.Equal(t, uint(0), result.RemainingCapacity)
+		assert.Equal(t, uint(2), getTireChangeTime(t, tireChangeTime.ID).BookedCount)
+
+		requestWriter = book(tireChangeTime, "third")
+		errorResult := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), errorResult)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, unAvailableTimeErrorCode, errorResult.Code)
+	})
+
+	t.Run("successfully book concurrently without exceeding capacity", func(t *testing.T) {
+		tireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		tireChangeTime.Capacity = 2
+		must(t, db.Create(tireChangeTime).Error)
+
+		codes := make(chan int, 5)
+		wg := sync.WaitGroup{}
+
+		for i := 0; i < cap(codes); i++ {
+			wg.Add(1)
+
+			go func(contactInformation string) {
+				defer wg.Done()
+				codes <- book(tireChangeTime, contactInformation).Code
+			}(fmt.Sprintf("concurrent-%d", i))
+		}
+
+		wg.Wait()
+		close(codes)
+
+		booked := 0
+
+		for code := range codes {
+			if code == http.StatusOK {
+				booked++
+			} else {
+				assert.Equal(t, http.StatusUnprocessableEntity, code)
+			}
+		}
+
+		result := getTireChangeTime(t, tireChangeTime.ID)
+
+		assert.Equal(t, 2, booked)
+		assert.Equal(t, uint(2), result.BookedCount)
+		assert.Len(t, result.Bookings, 2)
+		assert.False(t, result.Available)
+	})
+}
+
+func TestTireChangeTimeBookingLimit(t *testing.T) {
+	router := Init(true, Config{MaxActiveBookingsPerContact: 1})
+
+	book := func(tireChangeTime *tireChangeTimeEntity, contactInformation string) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", tireChangeTime.ID)
+		request := &tireChangeBookingRequest{ContactInformation: contactInformation}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	firstTireChangeTime := newTireChangeTimeEntity(time.Now().AddDate(1, 0, 0), true)
+	secondTireChangeTime := newTireChangeTimeEntity(time.Now().AddDate(1, 0, 1), true)
+	must(t, db.Create(firstTireChangeTime).Error)
+	must(t, db.Create(secondTireChangeTime).Error)
+
+	t.Run("successfully book tire change time within limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, book(firstTireChangeTime, "limited contact").Code)
+	})
+
+	t.Run("fail to book tire change time over limit", func(t *testing.T) {
+		requestWriter := book(secondTireChangeTime, "limited contact")
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, bookingLimitErrorCode, result.Code)
+		assert.NotEmpty(t, result.Message)
+		assert.True(t, getTireChangeTime(t, secondTireChangeTime.ID).Available)
+	})
+
+	t.Run("successfully book tire change time for another contact", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, book(secondTireChangeTime, "another contact").Code)
+	})
+
+	t.Run("successfully book concurrently without exceeding limit", func(t *testing.T) {
+		tireChangeTimes := make([]*tireChangeTimeEntity, 5)
+		codes := make(chan int, len(tireChangeTimes))
+		wg := sync.WaitGroup{}
+
+		for i := range tireChangeTimes {
+			tireChangeTimes[i] = newTireChangeTimeEntity(time.Now().AddDate(1, 0, i+2), true)
+			must(t, db.Create(tireChangeTimes[i]).Error)
+		}
+
+		for _, tireChangeTime := range tireChangeTimes {
+			wg.Add(1)
+
+			go func(tireChangeTime *tireChangeTimeEntity) {
+				defer wg.Done()
+				codes <- book(tireChangeTime, "concurrent contact").Code
+			}(tireChangeTime)
+		}
+
+		wg.Wait()
+		close(codes)
+
+		booked := 0
+
+		for code := range codes {
+			if code == http.StatusOK {
+				booked++
+			}
+		}
+
+		available := 0
+
+		for _, tireChangeTime := range tireChangeTimes {
+			if getTireChangeTime(t, tireChangeTime.ID).Available {
+				available++
+			}
+		}
+
+		assert.Equal(t, 1, booked)
+		assert.Equal(t, len(tireChangeTimes)-1, available)
+	})
+}
+
+func TestTireChangeTimeBookingWindow(t *testing.T) {
+	router := Init(true, Config{MinBookingLeadTime: 2 * time.Hour, MaxBookingAdvance: 30 * 24 * time.Hour})
+
+	book := func(tireChangeTime *tireChangeTimeEntity) *httptest.ResponseRecorder {
+		must(t, db.Create(tireChangeTime).Error)
+
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", tireChangeTime.ID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	t.Run("successfully book tire change time within booking window", func(t *testing.T) {
+		requestWriter := book(newTireChangeTimeEntity(time.Now().Add(3*time.Hour), true))
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+	})
+
+	t.Run("fail to book passed tire change time", func(t *testing.T) {
+		requestWriter := book(newTireChangeTimeEntity(time.Now().AddDate(0, 0, -1), true))
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, pastBookingErrorCode, result.Code)
+	})
+
+	t.Run("fail to book tire change time too soon", func(t *testing.T) {
+		requestWriter := book(newTireChangeTimeEntity(time.Now().Add(time.Hour), true))
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, bookingLeadTimeErrorCode, result.Code)
+	})
+
+	t.Run("fail to book tire change time too far in the future", func(t *testing.T) {
+		requestWriter := book(newTireChangeTimeEntity(time.Now().AddDate(0, 0, 60), true))
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, bookingAdvanceErrorCode, result.Code)
+	})
+
+	t.Run("fail to book unavailable tire change time suggesting alternatives within booking window", func(t *testing.T) {
+		requested := time.Now().Add(150 * time.Minute)
+		tooSoonTireChangeTime := newTireChangeTimeEntity(requested.Add(-time.Hour), true)
+		bookableTireChangeTime := newTireChangeTimeEntity(requested.Add(time.Minute), true)
+		must(t, db.Create(tooSoonTireChangeTime).Error)
+		must(t, db.Create(bookableTireChangeTime).Error)
+
+		requestWriter := book(newTireChangeTimeEntity(requested, false))
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+
+		var alternatives []uint
+
+		for _, alternative := range result.Alternatives {
+			alternatives = append(alternatives, alternative.ID)
+			assert.True(t, alternative.Time.After(time.Now().Add(2*time.Hour)))
+		}
+
+		assert.Contains(t, alternatives, bookableTireChangeTime.ID)
+		assert.NotContains(t, alternatives, tooSoonTireChangeTime.ID)
+	})
+
+}
+
+func TestServiceTypes(t *testing.T) {
+	router := Init(true, Config{})
+
+	book := func(tireChangeTime *tireChangeTimeEntity, serviceType string) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", tireChangeTime.ID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST", ServiceType: serviceType}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	consecutiveTimes := func(start time.Time, amount int) []*tireChangeTimeEntity {
+		var tireChangeTimes []*tireChangeTimeEntity
+
+		for i := 0; i < amount; i++ {
+			tireChangeTime := newTireChangeTimeEntity(start.Add(time.Duration(i)*tireChangeTimeDuration), true)
+			must(t, db.Create(tireChangeTime).Error)
+			tireChangeTimes = append(tireChangeTimes, tireChangeTime)
+		}
+
+		return tireChangeTimes
+	}
+
+	t.Run("successfully list service types", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/service-types", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &serviceTypesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, *result, 3)
+		assert.Equal(t, defaultServiceTypeCode, (*result)[0].Code)
+		assert.Equal(t, uint(60), (*result)[0].DurationMinutes)
+	})
+
+	t.Run("successfully list only start times fitting the service", func(t *testing.T) {
+		tomorrow := time.Now().AddDate(0, 0, 1)
+		start := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 22, 0, 0, 0, time.Local)
+		tireChangeTimes := consecutiveTimes(start, 2)
+
+		reqURL := fmt.Sprintf(
+			v2Path+"/tire-change-times?from=%s&serviceType=WHEEL_BALANCING",
+			tomorrow.Format(rfc3339DateFormat),
+		)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		var listedIDs []uint
+
+		for _, tireChangeTime := range *result {
+			assert.True(t, tireChangeTime.Available)
+			listedIDs = append(listedIDs, tireChangeTime.ID)
+		}
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Contains(t, listedIDs, tireChangeTimes[0].ID)
+		assert.NotContains(t, listedIDs, tireChangeTimes[1].ID)
+		verifyTireChangeTimesResponse(t, *result)
+	})
+
+	t.Run("successfully book and cancel service reserving consecutive tire change times", func(t *testing.T) {
+		tireChangeTimes := consecutiveTimes(time.Now().AddDate(0, 0, 3).Truncate(time.Hour).Add(time.Minute), 2)
+
+		requestWriter := book(tireChangeTimes[0], "WHEEL_BALANCING")
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.False(t, getTireChangeTime(t, tireChangeTimes[0].ID).Available)
+		assert.False(t, getTireChangeTime(t, tireChangeTimes[1].ID).Available)
+
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", tireChangeTimes[0].ID)
+		request := &tireChangeBookingCancellationRequest{ContactInformation: "TEST"}
+
+		requestWriter = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.True(t, getTireChangeTime(t, tireChangeTimes[0].ID).Available)
+		assert.True(t, getTireChangeTime(t, tireChangeTimes[1].ID).Available)
+	})
+
+	t.Run("fail to book service not fitting before next tire change time is booked", func(t *testing.T) {
+		tireChangeTimes := consecutiveTimes(time.Now().AddDate(0, 0, 4).Truncate(time.Hour).Add(time.Minute), 2)
+		bookTireChangeTime(t, tireChangeTimes[1], "some guy")
+
+		requestWriter := book(tireChangeTimes[0], "WHEEL_BALANCING")
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, serviceDoesNotFitErrorCode, result.Code)
+		assert.True(t, getTireChangeTime(t, tireChangeTimes[0].ID).Available)
+	})
+
+	t.Run("fail to book service overlapping another booking of the contact", func(t *testing.T) {
+		tireChangeTimes := consecutiveTimes(time.Now().AddDate(0, 0, 6).Truncate(time.Hour).Add(time.Minute), 2)
+		bookTireChangeTime(t, tireChangeTimes[0], "TEST")
+
+		requestWriter := book(tireChangeTimes[0], "WHEEL_BALANCING")
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, overlappingBookingErrorCode, result.Code)
+		assert.True(t, getTireChangeTime(t, tireChangeTimes[1].ID).Available)
+		assert.Empty(t, getTireChangeTime(t, tireChangeTimes[1].ID).Bookings)
+	})
+
+	t.Run("fail to book unknown service type", func(t *testing.T) {
+		tireChangeTimes := consecutiveTimes(time.Now().AddDate(0, 0, 5).Truncate(time.Hour).Add(time.Minute), 1)
+
+		requestWriter := book(tireChangeTimes[0], "UNKNOWN")
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.Equal(t, unknownServiceTypeErrorCode, result.Code)
+	})
+}
+
+func TestPriceQuotes(t *testing.T) {
+	router := Init(true, Config{})
+
+	book := func(tireChangeTime *tireChangeTimeEntity, vehicleType string) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", tireChangeTime.ID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST", VehicleType: vehicleType}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	// off-season day at noon is free of surcharges
+	nextYear := time.Now().Year() + 1
+	weekday := time.Date(nextYear, time.June, 10, 12, 0, 0, 0, time.Local)
+
+	for weekday.Weekday() == time.Saturday || weekday.Weekday() == time.Sunday {
+		weekday = weekday.AddDate(0, 0, 1)
+	}
+
+	saturday := time.Date(nextYear, time.July, 1, 12, 0, 0, 0, time.Local)
+
+	for saturday.Weekday() != time.Saturday {
+		saturday = saturday.AddDate(0, 0, 1)
+	}
+
+	t.Run("successfully quote listed tire change times for vehicle type", func(t *testing.T) {
+		reqURL := v2Path + "/tire-change-times?amount=50&page=1&vehicleType=VAN"
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, *result, 50)
+
+		serviceType := newServiceTypeRepository(db).oneByCode(defaultServiceTypeCode)
+
+		for _, tireChangeTime := range *result {
+			assert.Equal(t, pounds(newPricingEngine(DefaultPricing).quote(serviceType, "VAN", tireChangeTime.Time)), tireChangeTime.Price)
+		}
+	})
+
+	t.Run("successfully book tire change time with agreed price", func(t *testing.T) {
+		tireChangeTime := newTireChangeTimeEntity(weekday, true)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "SUV")
+		result := &tireChangeTimeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, 43.75, result.Price)
+		assert.Equal(t, int64(4375), getTireChangeTime(t, tireChangeTime.ID).bookingOf("TEST").PricePennies)
+		assert.Equal(t, "SUV", getTireChangeTime(t, tireChangeTime.ID).bookingOf("TEST").VehicleType)
+	})
+
+	t.Run("successfully book weekend tire change time with premium", func(t *testing.T) {
+		tireChangeTime := newTireChangeTimeEntity(saturday, true)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "")
+		result := &tireChangeTimeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, 45.5, result.Price)
+	})
+
+	t.Run("successfully quote peak hour surcharge by tire change time in server time zone", func(t *testing.T) {
+		useLocalTimeZone(t, time.FixedZone("UTC-5", -5*60*60))
+
+		// peak hour in server time zone stored in UTC is outside of peak hours
+		peakHour := time.Date(weekday.Year(), weekday.Month(), weekday.Day(), 16, 0, 0, 0, time.Local)
+		tireChangeTime := newTireChangeTimeEntity(peakHour.UTC(), true)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "")
+		result := &tireChangeTimeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, 38.5, result.Price)
+	})
+
+	t.Run("fail to book for unknown vehicle type", func(t *testing.T) {
+		tireChangeTime := newTireChangeTimeEntity(weekday.Add(time.Hour), true)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "TRACTOR")
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.Equal(t, unknownVehicleTypeErrorCode, result.Code)
+	})
+}
+
+func TestTireHotel(t *testing.T) {
+	router := Init(true, Config{})
+
+	registerTireSet := func(request *tireSetRequest) *httptest.ResponseRecorder {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, v2Path+"/tire-sets", marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	book := func(tireChangeTime *tireChangeTimeEntity, contactInformation string, tireSetID uint) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", tireChangeTime.ID)
+		request := &tireChangeBookingRequest{ContactInformation: contactInformation, TireSetID: tireSetID}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	attachTireSet := func(tireChangeTime *tireChangeTimeEntity, contactInformation string, tireSet *tireSetResponse) *httptest.ResponseRecorder {
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking/tire-set", tireChangeTime.ID)
+		request := &tireChangeBookingTireSetRequest{ContactInformation: contactInformation, TireSetID: tireSet.ID}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	storedTireSet := &tireSetRequest{
+		ContactInformation: "TEST",
+		Size:               "205/55 R16",
+		Brand:              "Nokian",
+		Condition:          "GOOD",
+		StorageLocation:    "A-12",
+	}
+
+	t.Run("successfully register and fetch tire set", func(t *testing.T) {
+		requestWriter := registerTireSet(storedTireSet)
+		registered := &tireSetResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), registered)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotZero(t, registered.ID)
+		assert.Equal(t, "A-12", registered.StorageLocation)
+
+		requestWriter = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf(v2Path+"/tire-sets/%d", registered.ID), nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireSetResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, registered, result)
+
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, v2Path+"/tire-sets?contactInformation=TEST", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		contactTireSets := &tireSetsResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), contactTireSets)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Contains(t, *contactTireSets, registered)
+	})
+
+	t.Run("successfully book tire change time with stored tire set", func(t *testing.T) {
+		tireSet := &tireSetResponse{}
+		unMarshal(t, registerTireSet(storedTireSet).Body.Bytes(), tireSet)
+
+		tireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "TEST", tireSet.ID)
+		result := &tireChangeTimeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, tireSet.ID, result.TireSetID)
+		assert.Equal(t, tireSet.ID, getTireChangeTime(t, tireChangeTime.ID).bookingOf("TEST").TireSetID)
+	})
+
+	t.Run("fail to book tire change time with tire set of another contact", func(t *testing.T) {
+		tireSet := &tireSetResponse{}
+		unMarshal(t, registerTireSet(storedTireSet).Body.Bytes(), tireSet)
+
+		tireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := book(tireChangeTime, "another guy", tireSet.ID)
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, invalidTireSetErrorCode, result.Code)
+		assert.True(t, getTireChangeTime(t, tireChangeTime.ID).Available)
+	})
+
+	t.Run("successfully attach stored tire set to booked tire change time", func(t *testing.T) {
+		tireSet := &tireSetResponse{}
+		unMarshal(t, registerTireSet(storedTireSet).Body.Bytes(), tireSet)
+
+		tireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(tireChangeTime).Error)
+		assert.Equal(t, http.StatusOK, book(tireChangeTime, "TEST", 0).Code)
+
+		requestWriter := attachTireSet(tireChangeTime, "TEST", tireSet)
+		result := &tireChangeTimeBookingResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, tireSet.ID, result.TireSetID)
+		assert.Equal(t, tireSet.ID, getTireChangeTime(t, tireChangeTime.ID).bookingOf("TEST").TireSetID)
+	})
+
+	t.Run("fail to attach tire set to tire change time not booked by the contact", func(t *testing.T) {
+		tireSet := &tireSetResponse{}
+		unMarshal(t, registerTireSet(storedTireSet).Body.Bytes(), tireSet)
+
+		tireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(tireChangeTime).Error)
+		bookTireChangeTime(t, tireChangeTime, "another guy")
+
+		requestWriter := attachTireSet(tireChangeTime, "TEST", tireSet)
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, notBookedByContactErrorCode, result.Code)
+		assert.Empty(t, getTireChangeTime(t, tireChangeTime.ID).bookingOf("another guy").TireSetID)
+	})
+
+	t.Run("fail to register tire set with unknown condition", func(t *testing.T) {
+		request := *storedTireSet
+		request.Condition = "SHINY"
+
+		assert.Equal(t, http.StatusBadRequest, registerTireSet(&request).Code)
+	})
+
+	t.Run("fail to get unknown tire set", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/tire-sets/999999", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		assert.Equal(t, unknownTireSetErrorCode, result.Code)
+	})
+}
+
+func TestWorkshop(t *testing.T) {
+	getWorkshop := func(config Config) (*httptest.ResponseRecorder, *workshopResponse) {
+		router := Init(true, config)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/workshop", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &workshopResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		return requestWriter, result
+	}
+
+	t.Run("successfully get default workshop information", func(t *testing.T) {
+		requestWriter, result := getWorkshop(Config{})
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, DefaultWorkshopInfo.Name, result.Name)
+		assert.Equal(t, DefaultWorkshopInfo.Latitude, result.Coordinates.Latitude)
+		assert.Equal(t, []string{"CAR", "SUV", "VAN"}, result.VehicleTypes)
+	})
+
+	t.Run("successfully get configured workshop information", func(t *testing.T) {
+		requestWriter, result := getWorkshop(Config{Workshop: WorkshopInfo{Name: "Salford tire workshop"}})
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, "Salford tire workshop", result.Name)
+		assert.Equal(t, DefaultWorkshopInfo.Address, result.Address)
+	})
+
+	t.Run("successfully get time zone of the server", func(t *testing.T) {
+		location, err := time.LoadLocation("America/New_York")
+		must(t, err)
+		useLocalTimeZone(t, location)
+
+		requestWriter, result := getWorkshop(Config{})
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, "America/New_York", result.TimeZone)
+	})
+}
+
+func TestContentNegotiation(t *testing.T) {
+	router := Init(true, Config{})
+
+	t.Run("successfully book tire change time with XML request and response", func(t *testing.T) {
+		availableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(availableTireChangeTime).Error)
+
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", availableTireChangeTime.ID)
+		requestXML, err := xml.Marshal(&tireChangeBookingRequest{ContactInformation: "TEST"})
+		must(t, err)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, bytes.NewBuffer(requestXML))
+		req.Header.Set("Content-Type", "application/xml")
+		req.Header.Set("Accept", "application/xml")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimeBookingResponse{}
+		must(t, xml.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Contains(t, requestWriter.Header().Get("Content-Type"), "application/xml")
+		assert.Equal(t, availableTireChangeTime.ID, result.ID)
+		assert.False(t, getTireChangeTime(t, availableTireChangeTime.ID).Available)
+	})
+
+	t.Run("successfully list tire change times in XML", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/tire-change-times?amount=3&page=1", nil)
+		req.Header.Set("Accept", "text/xml")
+		router.ServeHTTP(requestWriter, req)
+
+		var result struct {
+			XMLName         xml.Name                         `xml:"tireChangeTimes"`
+			TireChangeTimes []*tireChangeTimeBookingResponse `xml:"tireChangeTime"`
+		}
+		must(t, xml.Unmarshal(requestWriter.Body.Bytes(), &result))
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, result.TireChangeTimes, 3)
+	})
+
+	t.Run("successfully get JSON response when Accept header is missing", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/workshop", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Contains(t, requestWriter.Header().Get("Content-Type"), "application/json")
+	})
+
+	t.Run("successfully get error in XML format", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, v2Path+"/tire-change-times/0/booking", strings.NewReader("<tireChangeBookingRequest/>"))
+		req.Header.Set("Content-Type", "application/xml")
+		req.Header.Set("Accept", "application/xml")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		must(t, xml.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.NotEmpty(t, result.Code)
+		assert.NotEmpty(t, result.Message)
+	})
+}
+
+func TestConditionalGet(t *testing.T) {
+	router := Init(true, Config{})
+	reqURL := v2Path + "/tire-change-times?from=" + time.Now().Format(rfc3339DateFormat)
+
+	get := func(header string, value string) *httptest.ResponseRecorder {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+
+		router.ServeHTTP(requestWriter, req)
+
+		return requestWriter
+	}
+
+	t.Run("successfully get tire change times with validators and cache control", func(t *testing.T) {
+		requestWriter := get("", "")
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotEmpty(t, requestWriter.Header().Get("ETag"))
+		assert.NotEmpty(t, requestWriter.Header().Get("Last-Modified"))
+		assert.Equal(t, "no-cache", requestWriter.Header().Get("Cache-Control"))
+	})
+
+	t.Run("successfully get not modified tire change times by entity tag", func(t *testing.T) {
+		etag := get("", "").Header().Get("ETag")
+
+		requestWriter := get("If-None-Match", etag)
+
+		assert.Equal(t, http.StatusNotModified, requestWriter.Code)
+		assert.Empty(t, requestWriter.Body.Bytes())
+	})
+
+	t.Run("successfully get not modified tire change times by modification time", func(t *testing.T) {
+		lastModified := get("", "").Header().Get("Last-Modified")
+
+		requestWriter := get("If-Modified-Since", lastModified)
+
+		assert.Equal(t, http.StatusNotModified, requestWriter.Code)
+		assert.Empty(t, requestWriter.Body.Bytes())
+	})
+
+	t.Run("successfully get changed tire change times", func(t *testing.T) {
+		etag := get("", "").Header().Get("ETag")
+		tomorrow := time.Now().AddDate(0, 0, 1)
+		tireChangeTime := newTireChangeTimeEntity(
+			time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 12, 1, 0, 0, time.Local),
+			true,
+		)
+		must(t, db.Create(tireChangeTime).Error)
+
+		requestWriter := get("If-None-Match", etag)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotEqual(t, etag, requestWriter.Header().Get("ETag"))
+		assert.NotEmpty(t, requestWriter.Body.Bytes())
+	})
+
+	t.Run("successfully get tire change times modified since given time", func(t *testing.T) {
+		requestWriter := get("If-Modified-Since", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+	})
+}
+
+func TestProblemDetails(t *testing.T) {
+	router := Init(true, Config{})
+
+	t.Run("successfully describe invalid request by problem details in JSON", func(t *testing.T) {
+		availableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(availableTireChangeTime).Error)
+
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", availableTireChangeTime.ID)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/problem+json")
+		req.Header.Set(shared.TraceIDHeader, "trace-1")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &shared.Problem{}
+		must(t, json.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.Contains(t, requestWriter.Header().Get("Content-Type"), shared.MIMEProblemJSON)
+		assert.Equal(t, shared.ProblemTypeURNPrefix+"invalid-request", result.Type)
+		assert.Equal(t, "Request is invalid", result.Title)
+		assert.Equal(t, http.StatusBadRequest, result.Status)
+		assert.Equal(t, reqURL, result.Instance)
+		assert.Equal(t, "trace-1", result.TraceID)
+		assert.Equal(t, []*shared.InvalidParam{
+			{Name: "contactInformation", Reason: `property "contactInformation" is missing`},
+		}, result.InvalidParams)
+	})
+
+	t.Run("successfully describe unknown resource by problem details in XML", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/tire-sets/999999", nil)
+		req.Header.Set("Accept", "application/problem+xml")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &shared.Problem{}
+		must(t, xml.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		assert.Contains(t, requestWriter.Header().Get("Content-Type"), shared.MIMEProblemXML)
+		assert.Equal(t, shared.ProblemTypeURNPrefix+"unknown-tire-set", result.Type)
+		assert.Equal(t, http.StatusNotFound, result.Status)
+		assert.NotEmpty(t, result.Detail)
+		assert.NotEmpty(t, result.TraceID)
+	})
+
+	t.Run("successfully suggest alternatives by problem details extension member", func(t *testing.T) {
+		requested := time.Now().AddDate(23, 0, 0).Truncate(time.Hour)
+		unAvailableTireChangeTime := newTireChangeTimeEntity(requested, false)
+		alternativeTireChangeTime := newTireChangeTimeEntity(requested.Add(time.Hour), true)
+		must(t, db.Create(unAvailableTireChangeTime).Error)
+		must(t, db.Create(alternativeTireChangeTime).Error)
+
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", unAvailableTireChangeTime.ID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		req.Header.Set("Accept", "application/problem+json")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &struct {
+			shared.Problem
+			Alternatives []*tireChangeTimeBookingResponse `json:"alternatives"`
+		}{}
+		must(t, json.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, shared.ProblemTypeURNPrefix+"unavailable-tire-change-time", result.Type)
+		assert.NotEmpty(t, result.Alternatives)
+		assert.Equal(t, alternativeTireChangeTime.ID, result.Alternatives[len(result.Alternatives)-1].ID)
+	})
+
+	t.Run("successfully name invalid params by request keeping validation messages", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest(http.MethodGet, v2Path, nil)
+
+		err := binding.Validator.ValidateStruct(&tireChangeBookingRequest{})
+		problem := shared.NewProblem(c, "invalid-request", "Request is invalid", http.StatusBadRequest, err)
+
+		assert.Contains(t, err.Error(), "'ContactInformation'")
+		assert.Equal(t, "contactInformation", problem.InvalidParams[0].Name)
+	})
+
+	t.Run("successfully keep error format unless problem details are accepted explicitly", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/tire-sets/999999", nil)
+		req.Header.Set("Accept", "*/*")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		assert.NotEmpty(t, result.Message)
+	})
+}
+
+func TestVehicleTypeRestrictions(t *testing.T) {
+	router := Init(true, Config{})
+
+	nextYear := time.Now().AddDate(1, 0, 0)
+	day := time.Date(nextYear.Year(), nextYear.Month(), nextYear.Day(), 0, 0, 0, 0, time.Local)
+	restrictedTireChangeTime := newTireChangeTimeEntity(day.Add(20*time.Hour), true)
+	restrictedTireChangeTime.VehicleTypes = ",CAR,SUV,"
+	must(t, db.Create(restrictedTireChangeTime).Error)
+
+	listIDs := func(vehicleType string) []uint {
+		reqURL := fmt.Sprintf(
+			v2Path+"/tire-change-times?from=%s&vehicleType=%s",
+			day.Format(rfc3339DateFormat),
+			vehicleType,
+		)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+
+		var ids []uint
+
+		for _, tireChangeTime := range *result {
+			ids = append(ids, tireChangeTime.ID)
+
+			if tireChangeTime.ID == restrictedTireChangeTime.ID {
+				assert.Equal(t, []string{"CAR", "SUV"}, tireChangeTime.VehicleTypes)
+			}
+		}
+
+		return ids
+	}
+
+	t.Run("successfully list only tire change times supporting vehicle type", func(t *testing.T) {
+		assert.Contains(t, listIDs("SUV"), restrictedTireChangeTime.ID)
+		assert.NotContains(t, listIDs("VAN"), restrictedTireChangeTime.ID)
+	})
+
+	t.Run("fail to book tire change time for unsupported vehicle type", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", restrictedTireChangeTime.ID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST", VehicleType: "VAN"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, unsupportedVehicleErrorCode, result.Code)
+		assert.True(t, getTireChangeTime(t, restrictedTireChangeTime.ID).Available)
+	})
+}
+
+func TestMechanicShifts(t *testing.T) {
+	router := Init(true, Config{BaysPerTimeSlot: 2, AdminAPI: true})
+
+	nextYear := time.Now().AddDate(1, 0, 0)
+	day := time.Date(nextYear.Year(), nextYear.Month(), nextYear.Day(), 0, 0, 0, 0, time.Local)
+
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	tireChangeTime := newTireChangeTimeEntity(day.Add(10*time.Hour+time.Minute), true)
+	tireChangeTime.Capacity = 2
+	must(t, db.Create(tireChangeTime).Error)
+	bookTireChangeTime(t, tireChangeTime, "FIRST")
+	bookTireChangeTime(t, tireChangeTime, "SECOND")
+
+	getMechanics := func() mechanicsResponse {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/admin/mechanics", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		var result mechanicsResponse
+		unMarshal(t, requestWriter.Body.Bytes(), &result)
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+
+		return result
+	}
+
+	getRescheduledBookings := func() rescheduledBookingsResponse {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/admin/rescheduled-bookings", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		var result rescheduledBookingsResponse
+		unMarshal(t, requestWriter.Body.Bytes(), &result)
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+
+		return result
+	}
+
+	mechanics := getMechanics()
+	var absenceID uint
+
+	t.Run("successfully list mechanic operating every bay on weekdays", func(t *testing.T) {
+		assert.Len(t, mechanics, 2)
+		assert.Len(t, mechanics[0].Shifts, 5)
+		assert.Equal(t, &mechanicShiftResponse{Weekday: "MONDAY", StartHour: 8, EndHour: 17}, mechanics[0].Shifts[0])
+	})
+
+	t.Run("successfully flag latest booking for rescheduling when mechanic calls in sick", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v2Path+"/admin/mechanics/%d/absences", mechanics[0].ID)
+		request := &mechanicAbsenceRequest{Date: day.Format(rfc3339DateFormat), Type: absenceTypeSickLeave}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &mechanicAbsenceResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+		absenceID = result.ID
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, absenceTypeSickLeave, result.Type)
+		assert.Len(t, result.RescheduledBookings, 1)
+		assert.Equal(t, "SECOND", result.RescheduledBookings[0].ContactInformation)
+		assert.Equal(t, tireChangeTime.ID, result.RescheduledBookings[0].TireChangeTimeID)
+		assert.Equal(t, uint(1), getTireChangeTime(t, tireChangeTime.ID).Capacity)
+
+		rescheduled := getRescheduledBookings()
+		assert.Len(t, rescheduled, 1)
+		assert.Equal(t, "SECOND", rescheduled[0].ContactInformation)
+	})
+
+	t.Run("successfully restore bookings when substitute mechanic takes the shift", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, v2Path+"/admin/mechanics", marshal(t, &mechanicRequest{Name: "Substitute"}))
+		router.ServeHTTP(requestWriter, req)
+
+		substitute := &mechanicResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), substitute)
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Empty(t, substitute.Shifts)
+
+		reqURL := fmt.Sprintf(v2Path+"/admin/mechanics/%d/shifts", substitute.ID)
+		request := &mechanicShiftsRequest{Shifts: []*mechanicShiftRequest{
+			{Weekday: strings.ToUpper(day.Weekday().String()), StartHour: 10, EndHour: 12},
+		}}
+
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &mechanicResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, result.Shifts, 1)
+		assert.Equal(t, uint(2), getTireChangeTime(t, tireChangeTime.ID).Capacity)
+		assert.Empty(t, getRescheduledBookings())
+	})
+
+	t.Run("successfully cancel absence of the mechanic", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v2Path+"/admin/mechanics/%d/absences/%d", mechanics[0].ID, absenceID)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &mechanicResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Empty(t, result.Absences)
+		assert.Equal(t, uint(2), getTireChangeTime(t, tireChangeTime.ID).Capacity)
+	})
+
+	t.Run("successfully staff tire change time stored in UTC by shifts in server time zone", func(t *testing.T) {
+		useLocalTimeZone(t, time.FixedZone("UTC-5", -5*60*60))
+
+		// end of the shift in server time zone is outside of working hours in UTC
+		eveningTireChangeTime := newTireChangeTimeEntity(
+			time.Date(day.Year(), day.Month(), day.Day(), 16, 0, 0, 0, time.Local).UTC(),
+			true,
+		)
+		must(t, db.Create(eveningTireChangeTime).Error)
+
+		reqURL := fmt.Sprintf(v2Path+"/admin/mechanics/%d/shifts", mechanics[1].ID)
+		request := &mechanicShiftsRequest{Shifts: []*mechanicShiftRequest{
+			{Weekday: strings.ToUpper(day.Weekday().String()), StartHour: 8, EndHour: 17},
+		}}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, uint(2), getTireChangeTime(t, eveningTireChangeTime.ID).Capacity)
+	})
+
+	t.Run("fail to set shift ending before it starts", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v2Path+"/admin/mechanics/%d/shifts", mechanics[0].ID)
+		request := &mechanicShiftsRequest{Shifts: []*mechanicShiftRequest{
+			{Weekday: "MONDAY", StartHour: 12, EndHour: 10},
+		}}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.Equal(t, validationErrorCode, result.Code)
+	})
+
+	t.Run("fail to register absence of unknown mechanic", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v2Path+"/admin/mechanics/%d/absences", 999999)
+		request := &mechanicAbsenceRequest{Date: day.Format(rfc3339DateFormat), Type: absenceTypeDayOff}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		assert.Equal(t, unknownMechanicErrorCode, result.Code)
+	})
+
+	t.Run("fail to cancel unknown absence of the mechanic", func(t *testing.T) {
+		reqURL := fmt.Sprintf(v2Path+"/admin/mechanics/%d/absences/%d", mechanics[0].ID, 999999)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		assert.Equal(t, unknownMechanicAbsenceErrorCode, result.Code)
+	})
+
+	t.Run("fail to manage mechanics unless admin API is enabled", func(t *testing.T) {
+		router := Init(true, Config{})
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/admin/mechanics", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+	})
+}
+
+func TestBranches(t *testing.T) {
+	router := Init(true, Config{
+		Branches: []Config{{
+			BranchID: "salford",
+			Workshop: WorkshopInfo{Name: "Manchester Salford"},
+			Pricing:  Pricing{VehicleTypeFactors: map[string]int64{defaultVehicleType: 100, "VAN": 200}},
+		}},
+	})
+
+	salfordPath := "/branches/salford" + v2Path
+	from := time.Now().AddDate(0, 0, 1).Format(rfc3339DateFormat)
+
+	getAvailable := func(path string) tireChangeTimesResponse {
+		reqURL := fmt.Sprintf(path+"/tire-change-times?amount=50&page=1&from=%s", from)
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, reqURL, nil)
+		router.ServeHTTP(requestWriter, req)
+
+		var result tireChangeTimesResponse
+		unMarshal(t, requestWriter.Body.Bytes(), &result)
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+
+		return result
+	}
+
+	firstAvailable := func(tireChangeTimes tireChangeTimesResponse) *tireChangeTimeBookingResponse {
+		for _, tireChangeTime := range tireChangeTimes {
+			if tireChangeTime.Available {
+				return tireChangeTime
+			}
+		}
+
+		t.Fatal("no available tire change times")
+
+		return nil
+	}
+
+	t.Run("successfully list branches hosted by the server", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/branches", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		var result branchesResponse
+		unMarshal(t, requestWriter.Body.Bytes(), &result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Len(t, result, 2)
+		assert.Equal(t, DefaultBranchID, result[0].ID)
+		assert.Equal(t, DefaultWorkshopInfo.Name, result[0].Workshop.Name)
+		assert.Equal(t, "salford", result[1].ID)
+		assert.Equal(t, "Manchester Salford", result[1].Workshop.Name)
+	})
+
+	t.Run("successfully serve default branch by api routes", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/branches/"+DefaultBranchID+v2Path+"/workshop", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &workshopResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, DefaultWorkshopInfo.Name, result.Name)
+		assert.Equal(t, getAvailable(v2Path), getAvailable("/branches/"+DefaultBranchID+v2Path))
+	})
+
+	t.Run("successfully book tire change time of single branch", func(t *testing.T) {
+		tireChangeTime := firstAvailable(getAvailable(salfordPath))
+		reqURL := fmt.Sprintf(salfordPath+"/tire-change-times/%d/booking", tireChangeTime.ID)
+		request := &tireChangeBookingRequest{ContactInformation: "TEST"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotEqual(t, tireChangeTime.ID, firstAvailable(getAvailable(salfordPath)).ID)
+		assert.Equal(t, tireChangeTime.ID, firstAvailable(getAvailable(v2Path)).ID)
+		assert.True(t, getTireChangeTime(t, tireChangeTime.ID).Available)
+	})
+
+	t.Run("successfully quote tire change times by price list of the branch", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, salfordPath+"/tire-change-times?amount=50&page=1&vehicleType=VAN", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &tireChangeTimesResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotEmpty(t, *result)
+
+		for _, tireChangeTime := range *result {
+			assert.Equal(t, 70.0, tireChangeTime.Price)
+		}
+
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, salfordPath+"/tire-change-times?amount=50&page=1&vehicleType=SUV", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+	})
+
+	t.Run("fail to access unknown branch", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/branches/stockport"+v2Path+"/workshop", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		assert.Equal(t, unknownBranchErrorCode, result.Code)
+	})
+}
+
+func TestWaitlist(t *testing.T) {
+	router := Init(true, Config{})
+
+	t.Run("successfully assign cancelled tire change time to waitlisted contact", func(t *testing.T) {
+		bookedTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(bookedTireChangeTime).Error)
+		bookTireChangeTime(t, bookedTireChangeTime, "some guy")
+
+		request := &waitlistRequest{ContactInformation: "another guy", TireChangeTimeID: bookedTireChangeTime.ID}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, v2Path+"/waitlist", marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		entry := &waitlistEntryResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), entry)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, waitlistStatusWaiting, entry.Status)
+		assert.Nil(t, entry.AssignedTime)
+
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", bookedTireChangeTime.ID)
+		cancellation := &tireChangeBookingCancellationRequest{ContactInformation: "some guy"}
+
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodDelete, reqURL, marshal(t, cancellation))
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotNil(t, getTireChangeTime(t, bookedTireChangeTime.ID).bookingOf("another guy"))
+		assert.False(t, getTireChangeTime(t, bookedTireChangeTime.ID).Available)
+
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, fmt.Sprintf(v2Path+"/waitlist/%d", entry.ID), nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &waitlistEntryResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, waitlistStatusAssigned, result.Status)
+		assert.Equal(t, bookedTireChangeTime.ID, result.AssignedTime.ID)
+	})
+
+	t.Run("successfully assign free tire change time of requested day right away", func(t *testing.T) {
+		day := time.Now().AddDate(1, 0, 0)
+		availableTireChangeTime := newTireChangeTimeEntity(day, true)
+		must(t, db.Create(availableTireChangeTime).Error)
+
+		request := &waitlistRequest{ContactInformation: "TEST", Date: day.Format(rfc3339DateFormat)}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, v2Path+"/waitlist", marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &waitlistEntryResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, waitlistStatusAssigned, result.Status)
+		assert.Equal(t, availableTireChangeTime.ID, result.AssignedTime.ID)
+		assert.NotNil(t, getTireChangeTime(t, availableTireChangeTime.ID).bookingOf("TEST"))
+	})
+
+	t.Run("successfully assign cancelled tire change time to contact waitlisted for its day", func(t *testing.T) {
+		useLocalTimeZone(t, time.FixedZone("UTC-5", -5*60*60))
+
+		// late evening in server time zone stored in UTC falls on the next day
+		day := time.Now().AddDate(1, 0, 7)
+		bookedTireChangeTime := newTireChangeTimeEntity(
+			time.Date(day.Year(), day.Month(), day.Day(), 22, 0, 0, 0, time.Local).UTC(),
+			true,
+		)
+		must(t, db.Create(bookedTireChangeTime).Error)
+		bookTireChangeTime(t, bookedTireChangeTime, "some guy")
+
+		request := &waitlistRequest{ContactInformation: "day guy", Date: day.Format(rfc3339DateFormat)}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, v2Path+"/waitlist", marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		entry := &waitlistEntryResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), entry)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.Equal(t, waitlistStatusWaiting, entry.Status)
+
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", bookedTireChangeTime.ID)
+		cancellation := &tireChangeBookingCancellationRequest{ContactInformation: "some guy"}
+
+		requestWriter = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodDelete, reqURL, marshal(t, cancellation))
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusOK, requestWriter.Code)
+		assert.NotNil(t, getTireChangeTime(t, bookedTireChangeTime.ID).bookingOf("day guy"))
+	})
+
+	t.Run("fail to cancel tire change time booked by another contact", func(t *testing.T) {
+		bookedTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(bookedTireChangeTime).Error)
+		bookTireChangeTime(t, bookedTireChangeTime, "some guy")
+
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", bookedTireChangeTime.ID)
+		request := &tireChangeBookingCancellationRequest{ContactInformation: "another guy"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, reqURL, marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, requestWriter.Code)
+		assert.Equal(t, invalidCancellationErrorCode, result.Code)
+		assert.NotNil(t, getTireChangeTime(t, bookedTireChangeTime.ID).bookingOf("some guy"))
+	})
+
+	t.Run("fail to register without tire change time or date", func(t *testing.T) {
+		request := &waitlistRequest{ContactInformation: "TEST"}
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, v2Path+"/waitlist", marshal(t, request))
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.Equal(t, validationErrorCode, result.Code)
+	})
+
+	t.Run("fail to get unknown waitlist entry", func(t *testing.T) {
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf(v2Path+"/waitlist/%d", 34534523423), nil)
+		router.ServeHTTP(requestWriter, req)
+
+		result := &errorResponse{}
+		unMarshal(t, requestWriter.Body.Bytes(), result)
+
+		assert.Equal(t, http.StatusNotFound, requestWriter.Code)
+		assert.Equal(t, unknownWaitlistEntryErrorCode, result.Code)
+	})
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	router := Init(true, Config{})
+
+	t.Run("successfully serve OpenAPI document in YAML and JSON formats", func(t *testing.T) {
+		for path, contentType := range map[string]string{"/openapi.yaml": shared.MIMEYAML, "/openapi.json": "application/json"} {
+			requestWriter := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			router.ServeHTTP(requestWriter, req)
+
+			assert.Equal(t, http.StatusOK, requestWriter.Code)
+			assert.Contains(t, requestWriter.Header().Get("Content-Type"), contentType)
+			assert.Contains(t, requestWriter.Body.String(), "Manchester tire workshop API")
+		}
+	})
+
+	t.Run("fail to book tire change time by request body not conforming to OpenAPI document", func(t *testing.T) {
+		availableTireChangeTime := newTireChangeTimeEntity(time.Now().Add(time.Hour), true)
+		must(t, db.Create(availableTireChangeTime).Error)
+
+		reqURL := fmt.Sprintf(v2Path+"/tire-change-times/%d/booking", availableTireChangeTime.ID)
+		body := "<tireChangeBookingRequest><contactInformation>some guy</contactInformation>" +
+			"<tireSetId>unknown</tireSetId></tireChangeBookingRequest>"
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, reqURL, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/xml")
+		req.Header.Set("Accept", "application/problem+xml")
+		router.ServeHTTP(requestWriter, req)
+
+		result := &shared.Problem{}
+		must(t, xml.Unmarshal(requestWriter.Body.Bytes(), result))
+
+		assert.Equal(t, http.StatusBadRequest, requestWriter.Code)
+		assert.Len(t, result.InvalidParams, 1)
+		assert.Equal(t, "tireSetId", result.InvalidParams[0].Name)
+		assert.True(t, getTireChangeTime(t, availableTireChangeTime.ID).Available)
+	})
+
+	t.Run("fail to respond by route not described by OpenAPI document", func(t *testing.T) {
+		router.GET(v2Path+"/undocumented", func(ctx *gin.Context) { ctx.String(http.StatusOK, "undocumented") })
+
+		requestWriter := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, v2Path+"/undocumented", nil)
+		router.ServeHTTP(requestWriter, req)
+
+		assert.Equal(t, http.StatusInternalServerError, requestWriter.Code)
+		assert.Contains(t, requestWriter.Body.String(), "does not conform to OpenAPI document")
+	})
+}
+
+func bookTireChangeTime(t *testing.T, tireChangeTime *tireChangeTimeEntity, contactInformation string) {
+	must(t, tireChangeTime.makeBooking(
+		newTireChangeBookingEntity(tireChangeTime, contactInformation, defaultServiceTypeCode, defaultVehicleType, 35),
+		StrictBookingPolicy,
+	))
+	must(t, db.Save(tireChangeTime).Error)
+}
+
+func getTireChangeTime(t *testing.T, id uint) *tireChangeTimeEntity {
+	var result tireChangeTimeEntity
+
+	if err := db.Model(tireChangeTimeEntity{}).Preload("Bookings").Where("id = ?", id).Find(&result).Error; err != nil {
 		t.Fatalf("failed to fetch tire change time, error: %v", err)
 	}
 
@@ -227,6 +2331,24 @@ func verifyTireChangeTimesResponse(t *testing.T, result tireChangeTimesResponse)
 	}
 }
 
+// linkOf returns target of the Link header entry with given relation type, empty when there is no such entry
+func linkOf(requestWriter *httptest.ResponseRecorder, rel string) string {
+	match := regexp.MustCompile(`<([^>]*)>; rel="` + rel + `"`).FindStringSubmatch(requestWriter.Header().Get("Link"))
+
+	if match == nil {
+		return ""
+	}
+
+	return match[1]
+}
+
+// useLocalTimeZone runs the test in given server time zone, the original time zone is restored by test cleanup
+func useLocalTimeZone(t *testing.T, location *time.Location) {
+	original := time.Local
+	time.Local = location
+	t.Cleanup(func() { time.Local = original })
+}
+
 func must(t *testing.T, err error) {
 	if err != nil {
 		t.Fatalf("failed to run test task, error: %v", err)
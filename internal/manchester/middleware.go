@@ -3,6 +3,7 @@ package manchester
 import (
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
+	"github.com/surmus/tire-change-workshop/internal/shared"
 	"net/http"
 	"runtime/debug"
 )
@@ -16,7 +17,27 @@ func errorHandlerMiddleware() gin.HandlerFunc {
 				httpStatus, errorCode := httpStatus(err)
 
 				_ = c.Error(err)
-				c.AbortWithStatusJSON(httpStatus, errorResponse{Code: errorCode, Message: err.Error()})
+
+				if format := shared.ProblemFormat(c); format != "" {
+					problemType, title := problemTypeOf(errorCode)
+					problem := shared.NewProblem(c, problemType, title, httpStatus, err)
+
+					if appErr, ok := err.(*tireChangeApplicationError); ok && len(appErr.alternatives) > 0 {
+						problem.Alternatives = appErr.alternatives
+					}
+
+					shared.RenderProblem(c, format, problem)
+				} else {
+					response := errorResponse{Code: errorCode, Message: err.Error()}
+
+					if appErr, ok := err.(*tireChangeApplicationError); ok {
+						response.Alternatives = appErr.alternatives
+					}
+
+					render(c, httpStatus, response)
+				}
+
+				c.Abort()
 			}
 		}()
 
@@ -24,16 +45,53 @@ func errorHandlerMiddleware() gin.HandlerFunc {
 	}
 }
 
+const branchContextKey = "branch"
+
+// branchMiddleware resolves controller of the branch requested by branchId path parameter,
+// routes without the parameter are resolved by empty branch ID
+func branchMiddleware(branches map[string]*controller) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		branchID := c.Param("branchId")
+		branch, ok := branches[branchID]
+
+		if !ok {
+			panic(newUnknownBranchError(branchID))
+		}
+
+		c.Set(branchContextKey, branch)
+		c.Next()
+	}
+}
+
 func httpStatus(err error) (httpStatus int, errorCode string) {
 	if appErr, ok := err.(*tireChangeApplicationError); ok {
 		switch appErr.code {
-		case validationErrorCode:
+		case validationErrorCode, unknownServiceTypeErrorCode, unknownVehicleTypeErrorCode:
 			log.Infof("request encountered error: %s", err)
 			return http.StatusBadRequest, appErr.code
 
-		case unAvailableTimeErrorCode:
+		case unsupportedVehicleErrorCode,
+			unAvailableTimeErrorCode,
+			invalidCancellationErrorCode,
+			notBookedByContactErrorCode,
+			bookingLimitErrorCode,
+			pastBookingErrorCode,
+			bookingLeadTimeErrorCode,
+			bookingAdvanceErrorCode,
+			serviceDoesNotFitErrorCode,
+			invalidTireSetErrorCode,
+			overlappingBookingErrorCode:
 			log.Infof("request encountered error: %s", err)
 			return http.StatusUnprocessableEntity, appErr.code
+
+		case unknownWaitlistEntryErrorCode,
+			unknownTireSetErrorCode,
+			unknownMechanicErrorCode,
+			unknownMechanicAbsenceErrorCode,
+			unknownBranchErrorCode,
+			unknownTireChangeTimeErrorCode:
+			log.Infof("request encountered error: %s", err)
+			return http.StatusNotFound, appErr.code
 		}
 	}
 
@@ -1,25 +1,349 @@
 package manchester
 
-import "time"
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weeklySchedule limits tire change times to given weekdays and time of day in HH:MM format,
+// time of day is compared in server time zone and empty values allow all
+type weeklySchedule struct {
+	TimeFrom  string   `form:"timeFrom" binding:"omitempty,datetime=15:04"`
+	TimeUntil string   `form:"timeUntil" binding:"omitempty,datetime=15:04"`
+	Weekdays  []string `form:"weekdays" binding:"dive,oneof=MONDAY TUESDAY WEDNESDAY THURSDAY FRIDAY SATURDAY SUNDAY"`
+}
+
+// weekdays returns numbers of requested weekdays counted from Sunday as 0
+func (s *weeklySchedule) weekdays() []int {
+	var weekdays []int
+
+	for _, name := range s.Weekdays {
+		weekdays = append(weekdays, int(parseWeekday(name)))
+	}
+
+	return weekdays
+}
+
+// covers tells whether tire change time starting at given time fits the schedule
+func (s *weeklySchedule) covers(t time.Time) bool {
+	timeOfDay := t.Local().Format("15:04")
+
+	if s.TimeFrom != "" && timeOfDay < s.TimeFrom || s.TimeUntil != "" && timeOfDay >= s.TimeUntil {
+		return false
+	}
+
+	if len(s.Weekdays) == 0 {
+		return true
+	}
+
+	for _, weekday := range s.weekdays() {
+		if int(t.Local().Weekday()) == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseWeekday resolves weekday by its upper case English name, unknown names resolve to Sunday
+func parseWeekday(name string) time.Weekday {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.ToUpper(weekday.String()) == name {
+			return weekday
+		}
+	}
+
+	return time.Sunday
+}
 
 type tireChangeTimesSearchQuery struct {
-	Amount uint      `form:"amount"`
-	Page   uint      `form:"page" binding:"required_with=Amount"`
-	From   time.Time `form:"from" time_format:"2006-01-02"`
+	weeklySchedule
+	Amount      uint      `form:"amount"`
+	Page        *uint     `form:"page" binding:"required_with=Amount"`
+	Limit       uint      `form:"limit" binding:"required_with=Cursor,excluded_with=Amount"`
+	Cursor      string    `form:"cursor"`
+	From        time.Time `form:"from" time_format:"2006-01-02"`
+	Until       time.Time `form:"until" time_format:"2006-01-02" binding:"omitempty,gtefield=From"`
+	Available   *bool     `form:"available"`
+	ServiceType string    `form:"serviceType"`
+	VehicleType string    `form:"vehicleType"`
+}
+
+// end returns the beginning of the day following until date, zero time when until date is not given
+func (q *tireChangeTimesSearchQuery) end() time.Time {
+	if q.Until.IsZero() {
+		return time.Time{}
+	}
+
+	return q.Until.AddDate(0, 0, 1)
+}
+
+// page returns the number of pages to skip, first page when page is not given
+func (q *tireChangeTimesSearchQuery) page() uint {
+	if q.Page == nil {
+		return 0
+	}
+
+	return *q.Page
 }
 
 func (q *tireChangeTimesSearchQuery) offset() uint {
-	return q.Page * q.Amount
+	return q.page() * q.Amount
 }
 
 func (q *tireChangeTimesSearchQuery) isPaginated() bool {
 	return q.Amount > 0
 }
 
+func (q *tireChangeTimesSearchQuery) isCursorPaginated() bool {
+	return q.Limit > 0
+}
+
+// cursor decodes position of requested page, nil when cursor is not given
+func (q *tireChangeTimesSearchQuery) cursor() (*tireChangeTimesCursor, error) {
+	if q.Cursor == "" {
+		return nil, nil
+	}
+
+	return decodeTireChangeTimesCursor(q.Cursor)
+}
+
+// paginate selects requested page from already fetched tire change times, cursor paginated page is selected
+// with one extra tire change time revealing presence of further page
+func (q *tireChangeTimesSearchQuery) paginate(
+	entities []*tireChangeTimeEntity,
+	cursor *tireChangeTimesCursor,
+) []*tireChangeTimeEntity {
+	if q.isCursorPaginated() {
+		return q.paginateByCursor(entities, cursor)
+	}
+
+	if !q.isPaginated() {
+		return entities
+	}
+
+	if q.offset() >= uint(len(entities)) {
+		return entities[:0]
+	}
+
+	end := q.offset() + q.Amount
+
+	if end > uint(len(entities)) {
+		end = uint(len(entities))
+	}
+
+	return entities[q.offset():end]
+}
+
+func (q *tireChangeTimesSearchQuery) paginateByCursor(
+	entities []*tireChangeTimeEntity,
+	cursor *tireChangeTimesCursor,
+) []*tireChangeTimeEntity {
+	admitted := make([]*tireChangeTimeEntity, 0)
+
+	for _, entity := range entities {
+		if cursor == nil || cursor.admits(entity) {
+			admitted = append(admitted, entity)
+		}
+	}
+
+	size := int(q.Limit) + 1
+
+	if len(admitted) <= size {
+		return admitted
+	}
+
+	if cursor != nil && cursor.Backward {
+		return admitted[len(admitted)-size:]
+	}
+
+	return admitted[:size]
+}
+
+// tireChangeTimesCursor is a position between tire change times ordered by time and ID,
+// backward cursor points to tire change times preceding the position, forward cursor to following ones
+type tireChangeTimesCursor struct {
+	Time     time.Time
+	ID       uint
+	Backward bool
+}
+
+func newTireChangeTimesCursor(entity *tireChangeTimeEntity, backward bool) *tireChangeTimesCursor {
+	return &tireChangeTimesCursor{Time: entity.Time, ID: entity.ID, Backward: backward}
+}
+
+func decodeTireChangeTimesCursor(value string) (*tireChangeTimesCursor, error) {
+	invalidCursorError := fmt.Errorf("cursor %s is invalid", value)
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+
+	if err != nil {
+		return nil, invalidCursorError
+	}
+
+	parts := strings.Split(string(decoded), ":")
+
+	if len(parts) != 3 || (parts[0] != "prev" && parts[0] != "next") {
+		return nil, invalidCursorError
+	}
+
+	nanoseconds, err := strconv.ParseInt(parts[1], 10, 64)
+
+	if err != nil {
+		return nil, invalidCursorError
+	}
+
+	id, err := strconv.ParseUint(parts[2], 10, 64)
+
+	if err != nil {
+		return nil, invalidCursorError
+	}
+
+	return &tireChangeTimesCursor{Time: time.Unix(0, nanoseconds), ID: uint(id), Backward: parts[0] == "prev"}, nil
+}
+
+// String encodes the cursor into opaque URL safe value
+func (c *tireChangeTimesCursor) String() string {
+	direction := "next"
+
+	if c.Backward {
+		direction = "prev"
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%d:%d", direction, c.Time.UnixNano(), c.ID)))
+}
+
+// admits tells whether tire change time lies beyond the position in the direction of the cursor
+func (c *tireChangeTimesCursor) admits(entity *tireChangeTimeEntity) bool {
+	if c.Backward {
+		return entity.Time.Before(c.Time) || entity.Time.Equal(c.Time) && entity.ID < c.ID
+	}
+
+	return entity.Time.After(c.Time) || entity.Time.Equal(c.Time) && entity.ID > c.ID
+}
+
+// dailyAvailabilityQuery summarizes tire change times of days from date until date inclusive
+type dailyAvailabilityQuery struct {
+	From        time.Time `form:"from" time_format:"2006-01-02" binding:"required"`
+	Until       time.Time `form:"until" time_format:"2006-01-02" binding:"required,gtefield=From"`
+	VehicleType string    `form:"vehicleType"`
+}
+
+// end returns the beginning of the day following until date
+func (q *dailyAvailabilityQuery) end() time.Time {
+	return q.Until.AddDate(0, 0, 1)
+}
+
+// nextAvailableQuery looks up given amount of earliest available tire change times starting after given time
+type nextAvailableQuery struct {
+	After       time.Time `form:"after" time_format:"2006-01-02T15:04:05Z07:00"`
+	Count       uint      `form:"count,default=1" binding:"min=1,max=50"`
+	VehicleType string    `form:"vehicleType"`
+}
+
+type tireChangeTimeURI struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+// tireChangeTimeQuery selects service and vehicle type to quote tire change time price for
+type tireChangeTimeQuery struct {
+	ServiceType string `form:"serviceType"`
+	VehicleType string `form:"vehicleType"`
+}
+
 type tireChangeBookingURI struct {
 	ID uint `uri:"id" binding:"required"`
 }
 
 type tireChangeBookingRequest struct {
-	ContactInformation string `json:"contactInformation" binding:"required,min=1"`
+	ContactInformation string `json:"contactInformation" xml:"contactInformation" binding:"required,min=1"`
+	ServiceType        string `json:"serviceType" xml:"serviceType"`
+	VehicleType        string `json:"vehicleType" xml:"vehicleType"`
+	TireSetID          uint   `json:"tireSetId" xml:"tireSetId"`
+}
+
+// tireChangeBookingTireSetRequest attaches tire set stored in tire hotel to already booked tire change time
+type tireChangeBookingTireSetRequest struct {
+	ContactInformation string `json:"contactInformation" xml:"contactInformation" binding:"required,min=1"`
+	TireSetID          uint   `json:"tireSetId" xml:"tireSetId" binding:"required"`
+}
+
+type tireChangeBookingCancellationRequest struct {
+	ContactInformation string `json:"contactInformation" xml:"contactInformation" binding:"required,min=1"`
+}
+
+type waitlistEntryURI struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type waitlistRequest struct {
+	ContactInformation string `json:"contactInformation" xml:"contactInformation" binding:"required,min=1"`
+	TireChangeTimeID   uint   `json:"tireChangeTimeId" xml:"tireChangeTimeId" binding:"required_without=Date"`
+	Date               string `json:"date" xml:"date" binding:"required_without=TireChangeTimeID,omitempty,datetime=2006-01-02"`
+}
+
+func (r *waitlistRequest) day() time.Time {
+	if r.Date == "" {
+		return time.Time{}
+	}
+
+	day, _ := time.ParseInLocation("2006-01-02", r.Date, time.Local)
+
+	return day
+}
+
+type tireSetURI struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type tireSetsSearchQuery struct {
+	ContactInformation string `form:"contactInformation" binding:"required,min=1"`
+}
+
+type tireSetRequest struct {
+	ContactInformation string `json:"contactInformation" xml:"contactInformation" binding:"required,min=1"`
+	Size               string `json:"size" xml:"size" binding:"required,min=1"`
+	Brand              string `json:"brand" xml:"brand" binding:"required,min=1"`
+	Condition          string `json:"condition" xml:"condition" binding:"required,oneof=NEW GOOD WORN"`
+	StorageLocation    string `json:"storageLocation" xml:"storageLocation" binding:"required,min=1"`
+}
+
+type mechanicURI struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type mechanicAbsenceURI struct {
+	ID        uint `uri:"id" binding:"required"`
+	AbsenceID uint `uri:"absenceId" binding:"required"`
+}
+
+type mechanicRequest struct {
+	Name string `json:"name" xml:"name" binding:"required,min=1"`
+}
+
+type mechanicShiftsRequest struct {
+	Shifts []*mechanicShiftRequest `json:"shifts" xml:"shifts>shift" binding:"dive"`
+}
+
+type mechanicShiftRequest struct {
+	Weekday   string `json:"weekday" xml:"weekday" binding:"required,oneof=MONDAY TUESDAY WEDNESDAY THURSDAY FRIDAY SATURDAY SUNDAY"`
+	StartHour uint   `json:"startHour" xml:"startHour" binding:"max=23"`
+	EndHour   uint   `json:"endHour" xml:"endHour" binding:"required,gtfield=StartHour,max=24"`
+}
+
+func (r *mechanicShiftRequest) weekday() time.Weekday {
+	return parseWeekday(r.Weekday)
+}
+
+type mechanicAbsenceRequest struct {
+	Date string `json:"date" xml:"date" binding:"required,datetime=2006-01-02"`
+	Type string `json:"type" xml:"type" binding:"required,oneof=DAY_OFF SICK_LEAVE"`
+}
+
+func (r *mechanicAbsenceRequest) day() time.Time {
+	day, _ := time.ParseInLocation("2006-01-02", r.Date, time.Local)
+
+	return day
 }
@@ -1,36 +1,438 @@
 package manchester
 
-import "time"
+import (
+	"encoding/xml"
+	"github.com/surmus/tire-change-workshop/internal/shared"
+	"sort"
+	"strings"
+	"time"
+)
 
 type errorResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code    string `json:"code" xml:"code"`
+	Message string `json:"message" xml:"message"`
+	// Alternatives lists nearest available tire change times when requested one is unavailable
+	Alternatives []*tireChangeTimeBookingResponse `json:"alternatives,omitempty" xml:"alternatives>alternative,omitempty"`
 }
 
 type tireChangeTimeBookingResponse struct {
-	ID        uint      `json:"id"`
-	Time      time.Time `json:"time"`
-	Available bool      `json:"available"`
+	ID                uint      `json:"id" xml:"id"`
+	Time              time.Time `json:"time" xml:"time"`
+	Available         bool      `json:"available" xml:"available"`
+	RemainingCapacity uint      `json:"remainingCapacity" xml:"remainingCapacity"`
+	Price             float64   `json:"price,omitempty" xml:"price,omitempty"`
+	TireSetID         uint      `json:"tireSetId,omitempty" xml:"tireSetId,omitempty"`
+	VehicleTypes      []string  `json:"vehicleTypes,omitempty" xml:"vehicleTypes>vehicleType,omitempty"`
 }
 
-func newTireChangeTimeResponse(entity *tireChangeTimeEntity) *tireChangeTimeBookingResponse {
+func newTireChangeTimeResponse(entity *tireChangeTimeEntity, pricePennies int64) *tireChangeTimeBookingResponse {
 	return &tireChangeTimeBookingResponse{
-		ID:        entity.ID,
-		Time:      entity.Time.UTC(),
-		Available: entity.Available,
+		ID:                entity.ID,
+		Time:              entity.Time.UTC(),
+		Available:         entity.Available,
+		RemainingCapacity: entity.remainingCapacity(),
+		Price:             pounds(pricePennies),
+		VehicleTypes:      entity.supportedVehicleTypes(),
 	}
 }
 
+// pounds converts price in pennies to decimal pounds returned by the API
+func pounds(pennies int64) float64 {
+	return float64(pennies) / 100
+}
+
 type tireChangeTimesResponse []*tireChangeTimeBookingResponse
 
-func newTireChangeTimesResponse(entities []*tireChangeTimeEntity) *tireChangeTimesResponse {
+// MarshalXML encodes the list as tireChangeTimes element wrapping tireChangeTime elements
+func (r tireChangeTimesResponse) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "tireChangeTimes"
+
+	return e.EncodeElement(struct {
+		Items []*tireChangeTimeBookingResponse `xml:"tireChangeTime"`
+	}{r}, start)
+}
+
+func newTireChangeTimesResponse(
+	entities []*tireChangeTimeEntity,
+	priceOf func(*tireChangeTimeEntity) int64,
+) *tireChangeTimesResponse {
 	var availableTimes []*tireChangeTimeBookingResponse
 
 	for _, entity := range entities {
-		availableTimes = append(availableTimes, newTireChangeTimeResponse(entity))
+		availableTimes = append(availableTimes, newTireChangeTimeResponse(entity, priceOf(entity)))
 	}
 
 	response := tireChangeTimesResponse(availableTimes)
 
 	return &response
 }
+
+// tireChangeTimesPagination tells total amount of tire change times matching the search and cursors of pages
+// preceding and following the returned one, nil when there is no such page
+type tireChangeTimesPagination struct {
+	total uint
+	limit uint
+	prev  *tireChangeTimesCursor
+	next  *tireChangeTimesCursor
+}
+
+// newTireChangeTimesPagination cuts the extra tire change time fetched for cursor paginated page and resolves
+// cursors of neighbouring pages
+func newTireChangeTimesPagination(
+	query *tireChangeTimesSearchQuery,
+	cursor *tireChangeTimesCursor,
+	entities []*tireChangeTimeEntity,
+	total uint,
+) ([]*tireChangeTimeEntity, *tireChangeTimesPagination) {
+	pagination := &tireChangeTimesPagination{total: total}
+	var hasPrev, hasNext bool
+
+	switch {
+	case query.isCursorPaginated():
+		pagination.limit = query.Limit
+		backward := cursor != nil && cursor.Backward
+		overflow := uint(len(entities)) > query.Limit
+
+		if overflow && backward {
+			entities = entities[1:]
+		} else if overflow {
+			entities = entities[:query.Limit]
+		}
+
+		hasPrev = backward && overflow || cursor != nil && !backward
+		hasNext = backward || overflow
+
+	case query.isPaginated():
+		pagination.limit = query.Amount
+		hasPrev = query.page() > 0
+		hasNext = query.offset()+uint(len(entities)) < total
+	}
+
+	if len(entities) > 0 && hasPrev {
+		pagination.prev = newTireChangeTimesCursor(entities[0], true)
+	}
+
+	if len(entities) > 0 && hasNext {
+		pagination.next = newTireChangeTimesCursor(entities[len(entities)-1], false)
+	}
+
+	return entities, pagination
+}
+
+type dayAvailabilityResponse struct {
+	Date  string `json:"date" xml:"date"`
+	Total uint   `json:"total" xml:"total"`
+	// Available counts upcoming tire change times having free places left
+	Available uint `json:"available" xml:"available"`
+	// Booked counts tire change times having all places booked
+	Booked uint `json:"booked" xml:"booked"`
+}
+
+func newDayAvailabilityResponse(aggregate *dailyAvailability) *dayAvailabilityResponse {
+	return &dayAvailabilityResponse{
+		Date:      aggregate.Date,
+		Total:     aggregate.Total,
+		Available: aggregate.Available,
+		Booked:    aggregate.Booked,
+	}
+}
+
+type dailyAvailabilityResponse []*dayAvailabilityResponse
+
+// MarshalXML encodes the list as dailyAvailability element wrapping day elements
+func (r dailyAvailabilityResponse) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "dailyAvailability"
+
+	return e.EncodeElement(struct {
+		Items []*dayAvailabilityResponse `xml:"day"`
+	}{r}, start)
+}
+
+func newDailyAvailabilityResponse(aggregates []*dailyAvailability) *dailyAvailabilityResponse {
+	days := make([]*dayAvailabilityResponse, 0, len(aggregates))
+
+	for _, aggregate := range aggregates {
+		days = append(days, newDayAvailabilityResponse(aggregate))
+	}
+
+	response := dailyAvailabilityResponse(days)
+
+	return &response
+}
+
+type waitlistEntryResponse struct {
+	ID               uint                           `json:"id" xml:"id"`
+	Status           string                         `json:"status" xml:"status"`
+	TireChangeTimeID uint                           `json:"tireChangeTimeId,omitempty" xml:"tireChangeTimeId,omitempty"`
+	Date             string                         `json:"date,omitempty" xml:"date,omitempty"`
+	AssignedTime     *tireChangeTimeBookingResponse `json:"assignedTime,omitempty" xml:"assignedTime,omitempty"`
+}
+
+func newWaitlistEntryResponse(
+	entry *waitlistEntryEntity,
+	assignedTireChangeTime *tireChangeTimeEntity,
+) *waitlistEntryResponse {
+	response := &waitlistEntryResponse{
+		ID:               entry.ID,
+		Status:           entry.Status,
+		TireChangeTimeID: entry.TireChangeTimeID,
+	}
+
+	if !entry.Date.IsZero() {
+		response.Date = entry.Date.Format("2006-01-02")
+	}
+
+	if assignedTireChangeTime != nil {
+		response.AssignedTime = newTireChangeTimeResponse(assignedTireChangeTime, 0)
+
+		if booking := assignedTireChangeTime.bookingOf(entry.ContactInformation); booking != nil {
+			response.AssignedTime.Price = pounds(booking.PricePennies)
+		}
+	}
+
+	return response
+}
+
+type serviceTypeResponse struct {
+	Code            string  `json:"code" xml:"code"`
+	Name            string  `json:"name" xml:"name"`
+	DurationMinutes uint    `json:"durationMinutes" xml:"durationMinutes"`
+	Price           float64 `json:"price" xml:"price"`
+}
+
+type serviceTypesResponse []*serviceTypeResponse
+
+// MarshalXML encodes the list as serviceTypes element wrapping serviceType elements
+func (r serviceTypesResponse) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "serviceTypes"
+
+	return e.EncodeElement(struct {
+		Items []*serviceTypeResponse `xml:"serviceType"`
+	}{r}, start)
+}
+
+func newServiceTypesResponse(entities []*serviceTypeEntity) *serviceTypesResponse {
+	var serviceTypes []*serviceTypeResponse
+
+	for _, entity := range entities {
+		serviceTypes = append(serviceTypes, &serviceTypeResponse{
+			Code:            entity.Code,
+			Name:            entity.Name,
+			DurationMinutes: entity.DurationMinutes,
+			Price:           pounds(entity.PricePennies),
+		})
+	}
+
+	response := serviceTypesResponse(serviceTypes)
+
+	return &response
+}
+
+type tireSetResponse struct {
+	ID                 uint   `json:"id" xml:"id"`
+	ContactInformation string `json:"contactInformation" xml:"contactInformation"`
+	Size               string `json:"size" xml:"size"`
+	Brand              string `json:"brand" xml:"brand"`
+	Condition          string `json:"condition" xml:"condition"`
+	StorageLocation    string `json:"storageLocation" xml:"storageLocation"`
+}
+
+func newTireSetResponse(entity *tireSetEntity) *tireSetResponse {
+	return &tireSetResponse{
+		ID:                 entity.ID,
+		ContactInformation: entity.ContactInformation,
+		Size:               entity.Size,
+		Brand:              entity.Brand,
+		Condition:          entity.Condition,
+		StorageLocation:    entity.StorageLocation,
+	}
+}
+
+type tireSetsResponse []*tireSetResponse
+
+// MarshalXML encodes the list as tireSets element wrapping tireSet elements
+func (r tireSetsResponse) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "tireSets"
+
+	return e.EncodeElement(struct {
+		Items []*tireSetResponse `xml:"tireSet"`
+	}{r}, start)
+}
+
+func newTireSetsResponse(entities []*tireSetEntity) *tireSetsResponse {
+	var tireSets []*tireSetResponse
+
+	for _, entity := range entities {
+		tireSets = append(tireSets, newTireSetResponse(entity))
+	}
+
+	response := tireSetsResponse(tireSets)
+
+	return &response
+}
+
+type coordinatesResponse struct {
+	Latitude  float64 `json:"latitude" xml:"latitude"`
+	Longitude float64 `json:"longitude" xml:"longitude"`
+}
+
+type workshopResponse struct {
+	Name         string              `json:"name" xml:"name"`
+	Address      string              `json:"address" xml:"address"`
+	Coordinates  coordinatesResponse `json:"coordinates" xml:"coordinates"`
+	OpeningHours string              `json:"openingHours" xml:"openingHours"`
+	TimeZone     string              `json:"timeZone" xml:"timeZone"`
+	VehicleTypes []string            `json:"vehicleTypes" xml:"vehicleTypes>vehicleType"`
+}
+
+func newWorkshopResponse(info WorkshopInfo, vehicleTypes []string) *workshopResponse {
+	return &workshopResponse{
+		Name:         info.Name,
+		Address:      info.Address,
+		Coordinates:  coordinatesResponse{Latitude: info.Latitude, Longitude: info.Longitude},
+		OpeningHours: info.OpeningHours,
+		TimeZone:     shared.ServerTimeZone(),
+		VehicleTypes: vehicleTypes,
+	}
+}
+
+type branchResponse struct {
+	ID       string            `json:"id" xml:"id"`
+	Workshop *workshopResponse `json:"workshop" xml:"workshop"`
+}
+
+type branchesResponse []*branchResponse
+
+// MarshalXML encodes the list as branches element wrapping branch elements
+func (r branchesResponse) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "branches"
+
+	return e.EncodeElement(struct {
+		Items []*branchResponse `xml:"branch"`
+	}{r}, start)
+}
+
+// newBranchesResponse lists branches with their workshop details ordered by branch ID
+func newBranchesResponse(workshops map[string]*workshopResponse) *branchesResponse {
+	var branches []*branchResponse
+
+	for id, workshop := range workshops {
+		branches = append(branches, &branchResponse{ID: id, Workshop: workshop})
+	}
+
+	sort.Slice(branches, func(i, j int) bool { return branches[i].ID < branches[j].ID })
+	response := branchesResponse(branches)
+
+	return &response
+}
+
+type mechanicShiftResponse struct {
+	Weekday   string `json:"weekday" xml:"weekday"`
+	StartHour uint   `json:"startHour" xml:"startHour"`
+	EndHour   uint   `json:"endHour" xml:"endHour"`
+}
+
+type mechanicAbsenceResponse struct {
+	ID                  uint                          `json:"id" xml:"id"`
+	Date                string                        `json:"date" xml:"date"`
+	Type                string                        `json:"type" xml:"type"`
+	RescheduledBookings []*rescheduledBookingResponse `json:"rescheduledBookings,omitempty" xml:"rescheduledBookings>booking,omitempty"`
+}
+
+func newMechanicAbsenceResponse(entity *mechanicAbsenceEntity) *mechanicAbsenceResponse {
+	return &mechanicAbsenceResponse{
+		ID:   entity.ID,
+		Date: entity.Date.Format("2006-01-02"),
+		Type: entity.Type,
+	}
+}
+
+type mechanicResponse struct {
+	ID       uint                       `json:"id" xml:"id"`
+	Name     string                     `json:"name" xml:"name"`
+	Shifts   []*mechanicShiftResponse   `json:"shifts" xml:"shifts>shift"`
+	Absences []*mechanicAbsenceResponse `json:"absences" xml:"absences>absence"`
+}
+
+func newMechanicResponse(entity *mechanicEntity) *mechanicResponse {
+	response := &mechanicResponse{ID: entity.ID, Name: entity.Name}
+
+	for _, shift := range entity.Shifts {
+		response.Shifts = append(response.Shifts, &mechanicShiftResponse{
+			Weekday:   strings.ToUpper(shift.Weekday.String()),
+			StartHour: shift.StartHour,
+			EndHour:   shift.EndHour,
+		})
+	}
+
+	for _, absence := range entity.Absences {
+		response.Absences = append(response.Absences, newMechanicAbsenceResponse(absence))
+	}
+
+	return response
+}
+
+type mechanicsResponse []*mechanicResponse
+
+// MarshalXML encodes the list as mechanics element wrapping mechanic elements
+func (r mechanicsResponse) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "mechanics"
+
+	return e.EncodeElement(struct {
+		Items []*mechanicResponse `xml:"mechanic"`
+	}{r}, start)
+}
+
+func newMechanicsResponse(entities []*mechanicEntity) *mechanicsResponse {
+	var mechanics []*mechanicResponse
+
+	for _, entity := range entities {
+		mechanics = append(mechanics, newMechanicResponse(entity))
+	}
+
+	response := mechanicsResponse(mechanics)
+
+	return &response
+}
+
+type rescheduledBookingResponse struct {
+	TireChangeTimeID   uint      `json:"tireChangeTimeId" xml:"tireChangeTimeId"`
+	Time               time.Time `json:"time" xml:"time"`
+	ContactInformation string    `json:"contactInformation" xml:"contactInformation"`
+	ServiceType        string    `json:"serviceType" xml:"serviceType"`
+	VehicleType        string    `json:"vehicleType" xml:"vehicleType"`
+}
+
+type rescheduledBookingsResponse []*rescheduledBookingResponse
+
+// MarshalXML encodes the list as rescheduledBookings element wrapping booking elements
+func (r rescheduledBookingsResponse) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "rescheduledBookings"
+
+	return e.EncodeElement(struct {
+		Items []*rescheduledBookingResponse `xml:"booking"`
+	}{r}, start)
+}
+
+// newRescheduledBookingsResponse lists bookings flagged for rescheduling held in given tire change times
+func newRescheduledBookingsResponse(entities []*tireChangeTimeEntity) rescheduledBookingsResponse {
+	var bookings rescheduledBookingsResponse
+
+	for _, entity := range entities {
+		for _, booking := range entity.Bookings {
+			if !booking.RescheduleRequired {
+				continue
+			}
+
+			bookings = append(bookings, &rescheduledBookingResponse{
+				TireChangeTimeID:   entity.ID,
+				Time:               entity.Time.UTC(),
+				ContactInformation: booking.ContactInformation,
+				ServiceType:        booking.ServiceTypeCode,
+				VehicleType:        booking.VehicleType,
+			})
+		}
+	}
+
+	return bookings
+}
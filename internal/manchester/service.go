@@ -2,34 +2,926 @@ package manchester
 
 import (
 	log "github.com/sirupsen/logrus"
+	"github.com/surmus/tire-change-workshop/internal/shared"
+	"sort"
+	"time"
 )
 
 type tireChangeTimesService struct {
-	repository *tireChangeTimeRepository
+	repository   *tireChangeTimeRepository
+	serviceTypes *serviceTypeRepository
+	tireSets     *tireSetRepository
+	waitlist     *waitlistService
+	rules        *bookingRules
+	pricing      *pricingEngine
 }
 
-func newTireChangeTimesService(repository *tireChangeTimeRepository) *tireChangeTimesService {
-	return &tireChangeTimesService{repository: repository}
+func newTireChangeTimesService(
+	repository *tireChangeTimeRepository,
+	serviceTypes *serviceTypeRepository,
+	tireSets *tireSetRepository,
+	waitlist *waitlistService,
+	rules *bookingRules,
+	pricing *pricingEngine,
+) *tireChangeTimesService {
+	return &tireChangeTimesService{
+		repository:   repository,
+		serviceTypes: serviceTypes,
+		tireSets:     tireSets,
+		waitlist:     waitlist,
+		rules:        rules,
+		pricing:      pricing,
+	}
 }
 
-func (s *tireChangeTimesService) get(query *tireChangeTimesSearchQuery) *tireChangeTimesResponse {
+func (s *tireChangeTimesService) get(
+	query *tireChangeTimesSearchQuery,
+) (*tireChangeTimesResponse, *tireChangeTimesPagination, error) {
 	log.Infof("fetching tire change times for query: %+v", query)
-	tireChangeTimes := s.repository.allBySearchQuery(query)
+	cursor, err := query.cursor()
+
+	if err != nil {
+		return nil, nil, newValidationError(err)
+	}
+
+	serviceType, err := s.serviceType(query.ServiceType)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vehicleType, err := s.vehicleType(query.VehicleType)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tireChangeTimes []*tireChangeTimeEntity
+	var total uint
+
+	if query.ServiceType == "" {
+		tireChangeTimes, total = s.repository.allBySearchQuery(query, cursor)
+	} else {
+		tireChangeTimes, total = s.fittingStartTimes(query, cursor, serviceType)
+	}
+
+	tireChangeTimes, pagination := newTireChangeTimesPagination(query, cursor, tireChangeTimes, total)
+
 	log.Infof("successfully fetched %d tire change times for query: %+v", len(tireChangeTimes), query)
 
-	return newTireChangeTimesResponse(tireChangeTimes)
+	return newTireChangeTimesResponse(tireChangeTimes, func(tireChangeTime *tireChangeTimeEntity) int64 {
+		return s.pricing.quote(serviceType, vehicleType, tireChangeTime.Time)
+	}), pagination, nil
 }
 
-func (s *tireChangeTimesService) book(id uint, contactInformation string) (*tireChangeTimeBookingResponse, error) {
-	log.Infof("trying to book tire change time with id: %d", id)
+// getByID returns tire change time with its price quoted for the service and vehicle type of given query
+func (s *tireChangeTimesService) getByID(
+	id uint,
+	query *tireChangeTimeQuery,
+) (*tireChangeTimeBookingResponse, error) {
 	tireChangeTime := s.repository.availableByID(id)
 
-	if bookingErr := tireChangeTime.makeBooking(contactInformation); bookingErr != nil {
-		return nil, bookingErr
+	if tireChangeTime == zeroTireChangeTimeEntity {
+		return nil, newUnknownTireChangeTimeError(id)
+	}
+
+	serviceType, err := s.serviceType(query.ServiceType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	vehicleType, err := s.vehicleType(query.VehicleType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newTireChangeTimeResponse(tireChangeTime, s.pricing.quote(serviceType, vehicleType, tireChangeTime.Time)), nil
+}
+
+// fittingStartTimes returns page of available start times fitting the service with the amount of all such times
+func (s *tireChangeTimesService) fittingStartTimes(
+	query *tireChangeTimesSearchQuery,
+	cursor *tireChangeTimesCursor,
+	serviceType *serviceTypeEntity,
+) ([]*tireChangeTimeEntity, uint) {
+	end := query.end()
+	lastTime := end
+
+	// service started at the end of the period may last beyond it
+	if !end.IsZero() {
+		lastTime = end.Add(serviceType.duration())
+	}
+
+	available := s.repository.allAvailableBetween(query.From, lastTime, query.VehicleType)
+	startTimes := make([]*tireChangeTimeEntity, 0)
+
+	for _, tireChangeTime := range fittingStartTimes(available, serviceType) {
+		if (end.IsZero() || tireChangeTime.Time.Before(end)) && query.covers(tireChangeTime.Time) {
+			startTimes = append(startTimes, tireChangeTime)
+		}
+	}
+
+	return query.paginate(startTimes, cursor), uint(len(startTimes))
+}
+
+// getDailyAvailability counts tire change times of the days of given query able to service its vehicle type
+func (s *tireChangeTimesService) getDailyAvailability(query *dailyAvailabilityQuery) (*dailyAvailabilityResponse, error) {
+	if _, err := s.vehicleType(query.VehicleType); err != nil {
+		return nil, err
+	}
+
+	days := s.repository.dailyAvailability(query.From, query.end(), time.Now(), query.VehicleType)
+	log.Infof("successfully summarized tire change times of %d days from %s until %s", len(days), query.From, query.Until)
+
+	return newDailyAvailabilityResponse(days), nil
+}
+
+// getNextAvailable returns earliest upcoming available tire change times after the time of given query
+func (s *tireChangeTimesService) getNextAvailable(query *nextAvailableQuery) (*tireChangeTimesResponse, error) {
+	serviceType, err := s.serviceType("")
+
+	if err != nil {
+		return nil, err
+	}
+
+	vehicleType, err := s.vehicleType(query.VehicleType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	after := shared.InServerTimeZone(query.After)
+
+	if now := time.Now(); after.Before(now) {
+		after = now
+	}
+
+	tireChangeTimes := s.repository.nextAvailable(after, query.VehicleType, query.Count)
+	log.Infof("successfully fetched %d next available tire change times after %s", len(tireChangeTimes), after)
+
+	return newTireChangeTimesResponse(tireChangeTimes, func(tireChangeTime *tireChangeTimeEntity) int64 {
+		return s.pricing.quote(serviceType, vehicleType, tireChangeTime.Time)
+	}), nil
+}
+
+// alternativesPerSide is the amount of alternatives suggested before as well as after unavailable tire change time
+const alternativesPerSide = 2
+
+// alternativesTo returns nearest start times around given tire change time bookable by the contact instead
+func (s *tireChangeTimesService) alternativesTo(
+	tireChangeTime *tireChangeTimeEntity,
+	serviceType *serviceTypeEntity,
+	vehicleType string,
+	contactInformation string,
+) []*tireChangeTimeBookingResponse {
+	alternatives := make([]*tireChangeTimeBookingResponse, 0)
+
+	if tireChangeTime == zeroTireChangeTimeEntity {
+		return alternatives
+	}
+
+	required := serviceType.requiredTireChangeTimes()
+	before, after := s.repository.nearestAvailable(
+		tireChangeTime.Time,
+		time.Now(),
+		vehicleType,
+		alternativesPerSide*required,
+	)
+
+	fitting := func(candidates []*tireChangeTimeEntity) []*tireChangeTimeEntity {
+		var results []*tireChangeTimeEntity
+
+		for _, candidate := range candidates {
+			if len(results) == alternativesPerSide {
+				break
+			}
+
+			if !s.fits(candidate, required, vehicleType) {
+				continue
+			}
+
+			if s.rules.check(s.repository, candidate, contactInformation) == nil {
+				results = append(results, candidate)
+			}
+		}
+
+		return results
 	}
 
-	tireChangeTime = s.repository.save(tireChangeTime)
+	before = fitting(before)
+
+	for i := len(before) - 1; i >= 0; i-- {
+		alternatives = append(alternatives, newTireChangeTimeResponse(
+			before[i],
+			s.pricing.quote(serviceType, vehicleType, before[i].Time),
+		))
+	}
+
+	for _, alternative := range fitting(after) {
+		alternatives = append(alternatives, newTireChangeTimeResponse(
+			alternative,
+			s.pricing.quote(serviceType, vehicleType, alternative.Time),
+		))
+	}
+
+	return alternatives
+}
+
+// fits tells whether tire change times from given start fit the service and vehicle type
+func (s *tireChangeTimesService) fits(start *tireChangeTimeEntity, required int, vehicleType string) bool {
+	if required == 1 {
+		return true
+	}
+
+	tireChangeTimes := s.repository.consecutiveFrom(start, required)
+
+	for _, tireChangeTime := range tireChangeTimes {
+		if !tireChangeTime.supports(vehicleType) {
+			return false
+		}
+	}
+
+	return len(tireChangeTimes) == required
+}
+
+// lastModified returns the latest change of tire change times affecting their search results
+func (s *tireChangeTimesService) lastModified() time.Time {
+	return s.repository.lastModified(time.Now())
+}
+
+func (s *tireChangeTimesService) getServiceTypes() *serviceTypesResponse {
+	return newServiceTypesResponse(s.serviceTypes.all())
+}
+
+func (s *tireChangeTimesService) book(id uint, request *tireChangeBookingRequest) (*tireChangeTimeBookingResponse, error) {
+	log.Infof("trying to book tire change time with id: %d for service: %s", id, request.ServiceType)
+	serviceType, err := s.serviceType(request.ServiceType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	vehicleType, err := s.vehicleType(request.VehicleType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tireSet := zeroTireSetEntity
+
+	if request.TireSetID != 0 {
+		tireSet = s.tireSets.oneByID(request.TireSetID)
+
+		if tireSet == zeroTireSetEntity || tireSet.ContactInformation != request.ContactInformation {
+			return nil, newInvalidTireSetError(request.TireSetID)
+		}
+	}
+
+	var tireChangeTime *tireChangeTimeEntity
+	var tireChangeTimes []*tireChangeTimeEntity
+
+	// tire change times are loaded, checked and reserved within single transaction, so concurrent bookings can
+	// neither take the same places nor bypass booking rules
+	err = s.repository.transaction(func(repository *tireChangeTimeRepository) error {
+		tireChangeTime = repository.availableByID(id)
+
+		if rulesErr := s.rules.check(repository, tireChangeTime, request.ContactInformation); rulesErr != nil {
+			return rulesErr
+		}
+
+		tireChangeTimes = []*tireChangeTimeEntity{tireChangeTime}
+
+		if required := serviceType.requiredTireChangeTimes(); required > 1 && tireChangeTime != zeroTireChangeTimeEntity {
+			if tireChangeTimes = repository.consecutiveFrom(tireChangeTime, required); len(tireChangeTimes) < required {
+				return newServiceDoesNotFitError(tireChangeTime, serviceType)
+			}
+		}
+
+		for _, reserved := range tireChangeTimes {
+			if !reserved.supports(vehicleType) {
+				return newUnsupportedVehicleTypeError(reserved, vehicleType)
+			}
+		}
+
+		if overlapping := overlappingBooking(tireChangeTimes, request.ContactInformation); overlapping != nil {
+			return newOverlappingBookingError(overlapping)
+		}
+
+		price := s.pricing.quote(serviceType, vehicleType, tireChangeTime.Time)
+
+		for _, reserved := range tireChangeTimes {
+			held := reserved.bookingOf(request.ContactInformation) != nil
+			booking := newTireChangeBookingEntity(
+				tireChangeTimes[0],
+				request.ContactInformation,
+				serviceType.Code,
+				vehicleType,
+				price,
+			)
+
+			if tireSet != zeroTireSetEntity {
+				booking.attachTireSet(tireSet)
+			}
+
+			if bookingErr := reserved.makeBooking(booking, s.rules.config.BookingPolicy); bookingErr != nil {
+				return bookingErr
+			} else if held {
+				continue
+			}
+
+			if !repository.reserve(reserved, booking) {
+				return newUnAvailableBookingError(reserved)
+			}
+		}
+
+		return nil
+	})
+
+	if appErr, ok := err.(*tireChangeApplicationError); ok && appErr.code == unAvailableTimeErrorCode {
+		appErr.alternatives = s.alternativesTo(tireChangeTime, serviceType, vehicleType, request.ContactInformation)
+	}
+
+	if err != nil {
+		return nil, err
+	}
 
 	log.Infof("successfully booked tire change time with id: %d", id)
-	return newTireChangeTimeResponse(tireChangeTime), nil
+	booking := tireChangeTimes[0].bookingOf(request.ContactInformation)
+	response := newTireChangeTimeResponse(tireChangeTimes[0], booking.PricePennies)
+	response.TireSetID = booking.TireSetID
+
+	return response, nil
+}
+
+// attachTireSet attaches tire set of the contact to all tire change times booked together with given one
+func (s *tireChangeTimesService) attachTireSet(
+	id uint,
+	request *tireChangeBookingTireSetRequest,
+) (*tireChangeTimeBookingResponse, error) {
+	log.Infof("trying to attach tire set %d to tire change time booking with id: %d", request.TireSetID, id)
+	tireSet := s.tireSets.oneByID(request.TireSetID)
+
+	if tireSet == zeroTireSetEntity || tireSet.ContactInformation != request.ContactInformation {
+		return nil, newInvalidTireSetError(request.TireSetID)
+	}
+
+	var tireChangeTime *tireChangeTimeEntity
+	var booking *tireChangeBookingEntity
+
+	err := s.repository.transaction(func(repository *tireChangeTimeRepository) error {
+		tireChangeTime = repository.availableByID(id)
+
+		if booking = tireChangeTime.bookingOf(request.ContactInformation); booking == nil {
+			return newNotBookedByContactError(tireChangeTime)
+		}
+
+		for _, reserved := range repository.allByBooking(booking) {
+			reservedBooking := reserved.bookingOf(request.ContactInformation)
+			reservedBooking.attachTireSet(tireSet)
+			repository.saveBooking(reservedBooking)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("successfully attached tire set %d to tire change time booking with id: %d", tireSet.ID, id)
+	response := newTireChangeTimeResponse(tireChangeTime, booking.PricePennies)
+	response.TireSetID = tireSet.ID
+
+	return response, nil
+}
+
+// overlappingBooking returns the first of tire change times booked by the contact for another service
+func overlappingBooking(tireChangeTimes []*tireChangeTimeEntity, contactInformation string) *tireChangeTimeEntity {
+	var overlapping *tireChangeTimeEntity
+	held := 0
+
+	for _, reserved := range tireChangeTimes {
+		if booking := reserved.bookingOf(contactInformation); booking == nil {
+			continue
+		} else if booking.StartTireChangeTimeID != tireChangeTimes[0].ID {
+			return reserved
+		} else if overlapping == nil {
+			overlapping = reserved
+		}
+
+		held++
+	}
+
+	if held < len(tireChangeTimes) {
+		return overlapping
+	}
+
+	return nil
+}
+
+// cancelBooking releases all tire change times reserved by the contact together with given tire change time
+func (s *tireChangeTimesService) cancelBooking(
+	id uint,
+	contactInformation string,
+) (*tireChangeTimeBookingResponse, error) {
+	log.Infof("trying to cancel tire change time booking with id: %d", id)
+	tireChangeTime := s.repository.availableByID(id)
+	booking := tireChangeTime.bookingOf(contactInformation)
+
+	if booking == nil {
+		return nil, newInvalidBookingCancellationError(tireChangeTime)
+	}
+
+	var released []*tireChangeTimeEntity
+
+	err := s.repository.transaction(func(repository *tireChangeTimeRepository) error {
+		for _, reserved := range repository.allByBooking(booking) {
+			releasedBooking, cancellationErr := reserved.cancelBooking(contactInformation)
+
+			if cancellationErr != nil {
+				return cancellationErr
+			}
+
+			repository.release(reserved, releasedBooking)
+			released = append(released, reserved)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// released places are offered to the waitlist only once the cancellation is committed
+	for _, reserved := range released {
+		s.waitlist.offer(reserved)
+
+		if reserved.ID == tireChangeTime.ID {
+			tireChangeTime = reserved
+		}
+	}
+
+	log.Infof("successfully cancelled tire change time booking with id: %d", id)
+
+	return newTireChangeTimeResponse(tireChangeTime, 0), nil
+}
+
+// serviceType resolves service type by code, empty code refers to default tire change service
+func (s *tireChangeTimesService) serviceType(code string) (*serviceTypeEntity, error) {
+	if code == "" {
+		code = defaultServiceTypeCode
+	}
+
+	if serviceType := s.serviceTypes.oneByCode(code); serviceType != zeroServiceTypeEntity {
+		return serviceType, nil
+	}
+
+	return nil, newUnknownServiceTypeError(code)
+}
+
+// vehicleType verifies vehicle type to be priced by the workshop, empty vehicle type refers to passenger car
+func (s *tireChangeTimesService) vehicleType(vehicleType string) (string, error) {
+	if vehicleType == "" {
+		return defaultVehicleType, nil
+	}
+
+	if !s.pricing.supports(vehicleType) {
+		return "", newUnknownVehicleTypeError(vehicleType)
+	}
+
+	return vehicleType, nil
+}
+
+type waitlistService struct {
+	repository               *waitlistRepository
+	tireChangeTimeRepository *tireChangeTimeRepository
+	serviceTypes             *serviceTypeRepository
+	rules                    *bookingRules
+	pricing                  *pricingEngine
+}
+
+func newWaitlistService(
+	repository *waitlistRepository,
+	tireChangeTimeRepository *tireChangeTimeRepository,
+	serviceTypes *serviceTypeRepository,
+	rules *bookingRules,
+	pricing *pricingEngine,
+) *waitlistService {
+	return &waitlistService{
+		repository:               repository,
+		tireChangeTimeRepository: tireChangeTimeRepository,
+		serviceTypes:             serviceTypes,
+		rules:                    rules,
+		pricing:                  pricing,
+	}
+}
+
+func (s *waitlistService) register(request *waitlistRequest) (*waitlistEntryResponse, error) {
+	log.Infof("registering waitlist entry for request: %+v", request)
+	entry := newWaitlistEntryEntity(request.ContactInformation, request.TireChangeTimeID, request.day())
+
+	var tireChangeTime *tireChangeTimeEntity
+
+	if entry.TireChangeTimeID != 0 {
+		if tireChangeTime = s.tireChangeTimeRepository.availableByID(entry.TireChangeTimeID); tireChangeTime == zeroTireChangeTimeEntity {
+			return nil, newUnAvailableBookingError(&tireChangeTimeEntity{ID: entry.TireChangeTimeID})
+		}
+	} else {
+		tireChangeTime = s.tireChangeTimeRepository.firstAvailableByDay(entry.Date)
+	}
+
+	// interest in currently free time is fulfilled right away
+	if tireChangeTime.Available && s.reserve(tireChangeTime, entry) == nil {
+		entry.assign(tireChangeTime)
+	}
+
+	entry = s.repository.save(entry)
+	log.Infof("successfully registered waitlist entry %d with status %s", entry.ID, entry.Status)
+
+	return s.newWaitlistEntryResponse(entry), nil
+}
+
+func (s *waitlistService) get(id uint) (*waitlistEntryResponse, error) {
+	entry := s.repository.oneByID(id)
+
+	if entry == zeroWaitlistEntryEntity {
+		return nil, newUnknownWaitlistEntryError(id)
+	}
+
+	return s.newWaitlistEntryResponse(entry), nil
+}
+
+// offer assigns released tire change time to the longest waiting matching waitlist entry allowed to book it
+func (s *waitlistService) offer(tireChangeTime *tireChangeTimeEntity) {
+	for _, entry := range s.repository.allWaitingFor(tireChangeTime) {
+		err := s.reserve(tireChangeTime, entry)
+
+		if appErr, ok := err.(*tireChangeApplicationError); ok && appErr.code == unAvailableTimeErrorCode {
+			log.Infof("could not assign tire change time %d to waitlist entry %d: %s", tireChangeTime.ID, entry.ID, err)
+			return
+		} else if err != nil {
+			log.Infof("skipping waitlist entry %d for tire change time %d: %s", entry.ID, tireChangeTime.ID, err)
+			continue
+		}
+
+		entry.assign(tireChangeTime)
+		s.repository.save(entry)
+
+		log.Infof("assigned tire change time %d to waitlist entry %d", tireChangeTime.ID, entry.ID)
+
+		return
+	}
+}
+
+// reserve books tire change time for waitlisted contact within transaction checking booking rules
+func (s *waitlistService) reserve(tireChangeTime *tireChangeTimeEntity, entry *waitlistEntryEntity) error {
+	booking := s.newBooking(tireChangeTime, entry)
+
+	return s.tireChangeTimeRepository.transaction(func(repository *tireChangeTimeRepository) error {
+		if err := s.rules.check(repository, tireChangeTime, entry.ContactInformation); err != nil {
+			return err
+		}
+
+		held := tireChangeTime.bookingOf(entry.ContactInformation) != nil
+
+		if err := tireChangeTime.makeBooking(booking, s.rules.config.BookingPolicy); err != nil || held {
+			return err
+		}
+
+		if !repository.reserve(tireChangeTime, booking) {
+			return newUnAvailableBookingError(tireChangeTime)
+		}
+
+		return nil
+	})
+}
+
+// newBooking books tire change of a passenger car for waitlisted contact at the price of assignment time
+func (s *waitlistService) newBooking(
+	tireChangeTime *tireChangeTimeEntity,
+	entry *waitlistEntryEntity,
+) *tireChangeBookingEntity {
+	serviceType := s.serviceTypes.oneByCode(defaultServiceTypeCode)
+
+	return newTireChangeBookingEntity(
+		tireChangeTime,
+		entry.ContactInformation,
+		serviceType.Code,
+		defaultVehicleType,
+		s.pricing.quote(serviceType, defaultVehicleType, tireChangeTime.Time),
+	)
+}
+
+func (s *waitlistService) newWaitlistEntryResponse(entry *waitlistEntryEntity) *waitlistEntryResponse {
+	if entry.Status != waitlistStatusAssigned {
+		return newWaitlistEntryResponse(entry, nil)
+	}
+
+	return newWaitlistEntryResponse(entry, s.tireChangeTimeRepository.availableByID(entry.AssignedTireChangeTimeID))
+}
+
+// tireHotelService keeps track of customers tire sets stored by the workshop
+type tireHotelService struct {
+	repository *tireSetRepository
+}
+
+func newTireHotelService(repository *tireSetRepository) *tireHotelService {
+	return &tireHotelService{repository: repository}
+}
+
+func (s *tireHotelService) register(request *tireSetRequest) *tireSetResponse {
+	log.Infof("registering tire set for request: %+v", request)
+	tireSet := s.repository.save(newTireSetEntity(
+		request.ContactInformation,
+		request.Size,
+		request.Brand,
+		request.Condition,
+		request.StorageLocation,
+	))
+	log.Infof("successfully registered tire set %d at %s", tireSet.ID, tireSet.StorageLocation)
+
+	return newTireSetResponse(tireSet)
+}
+
+func (s *tireHotelService) get(id uint) (*tireSetResponse, error) {
+	tireSet := s.repository.oneByID(id)
+
+	if tireSet == zeroTireSetEntity {
+		return nil, newUnknownTireSetError(id)
+	}
+
+	return newTireSetResponse(tireSet), nil
+}
+
+func (s *tireHotelService) getByContact(query *tireSetsSearchQuery) *tireSetsResponse {
+	return newTireSetsResponse(s.repository.allByContact(query.ContactInformation))
+}
+
+// workshopService publishes workshop details for API clients
+type workshopService struct {
+	info    WorkshopInfo
+	pricing *pricingEngine
+}
+
+func newWorkshopService(info WorkshopInfo, pricing *pricingEngine) *workshopService {
+	return &workshopService{info: info, pricing: pricing}
+}
+
+func (s *workshopService) get() *workshopResponse {
+	return newWorkshopResponse(s.info, s.pricing.vehicleTypes())
+}
+
+// staffService manages mechanics, whose shifts and absences limit bays available at tire change times
+type staffService struct {
+	repository               *mechanicRepository
+	tireChangeTimeRepository *tireChangeTimeRepository
+	waitlist                 *waitlistService
+	baysPerTimeSlot          uint
+}
+
+func newStaffService(
+	repository *mechanicRepository,
+	tireChangeTimeRepository *tireChangeTimeRepository,
+	waitlist *waitlistService,
+	baysPerTimeSlot uint,
+) *staffService {
+	return &staffService{
+		repository:               repository,
+		tireChangeTimeRepository: tireChangeTimeRepository,
+		waitlist:                 waitlist,
+		baysPerTimeSlot:          baysPerTimeSlot,
+	}
+}
+
+func (s *staffService) getAll() *mechanicsResponse {
+	return newMechanicsResponse(s.repository.all())
+}
+
+func (s *staffService) register(request *mechanicRequest) *mechanicResponse {
+	mechanic := s.repository.save(newMechanicEntity(request.Name))
+	log.Infof("successfully registered mechanic %d", mechanic.ID)
+
+	return newMechanicResponse(mechanic)
+}
+
+// replaceShifts sets weekly shifts of the mechanic, capacity of all future tire change times is adjusted accordingly
+func (s *staffService) replaceShifts(id uint, request *mechanicShiftsRequest) (*mechanicResponse, error) {
+	mechanic := s.repository.oneByID(id)
+
+	if mechanic == zeroMechanicEntity {
+		return nil, newUnknownMechanicError(id)
+	}
+
+	shifts := make([]*mechanicShiftEntity, 0, len(request.Shifts))
+
+	for _, shift := range request.Shifts {
+		shifts = append(shifts, newMechanicShiftEntity(mechanic, shift.weekday(), shift.StartHour, shift.EndHour))
+	}
+
+	mechanic = s.repository.replaceShifts(mechanic, shifts)
+	log.Infof("successfully replaced shifts of mechanic %d", mechanic.ID)
+	s.adjustCapacity(time.Now(), time.Time{})
+
+	return newMechanicResponse(mechanic), nil
+}
+
+// registerAbsence takes mechanic off duty for a day, returning bookings flagged for rescheduling
+func (s *staffService) registerAbsence(id uint, request *mechanicAbsenceRequest) (*mechanicAbsenceResponse, error) {
+	mechanic := s.repository.oneByID(id)
+
+	if mechanic == zeroMechanicEntity {
+		return nil, newUnknownMechanicError(id)
+	}
+
+	absence := newMechanicAbsenceEntity(mechanic, request.day(), request.Type)
+	mechanic.Absences = append(mechanic.Absences, absence)
+	s.repository.save(mechanic)
+	log.Infof("registered %s of mechanic %d on %s", absence.Type, mechanic.ID, request.Date)
+
+	response := newMechanicAbsenceResponse(absence)
+	response.RescheduledBookings = newRescheduledBookingsResponse(
+		s.adjustCapacity(absence.Date, absence.Date.AddDate(0, 0, 1)),
+	)
+
+	return response, nil
+}
+
+// removeAbsence returns mechanic on duty, released bays are offered to waitlisted contacts
+func (s *staffService) removeAbsence(id uint, absenceID uint) (*mechanicResponse, error) {
+	mechanic := s.repository.oneByID(id)
+
+	if mechanic == zeroMechanicEntity {
+		return nil, newUnknownMechanicError(id)
+	}
+
+	absence := mechanic.absenceOf(absenceID)
+
+	if absence == nil {
+		return nil, newUnknownMechanicAbsenceError(mechanic, absenceID)
+	}
+
+	s.repository.deleteAbsence(absence)
+	log.Infof("removed absence %d of mechanic %d", absence.ID, mechanic.ID)
+	s.adjustCapacity(absence.Date, absence.Date.AddDate(0, 0, 1))
+
+	return newMechanicResponse(s.repository.oneByID(id)), nil
+}
+
+func (s *staffService) getRescheduledBookings() *rescheduledBookingsResponse {
+	response := newRescheduledBookingsResponse(s.tireChangeTimeRepository.allRequiringRescheduling(time.Now()))
+
+	return &response
+}
+
+// adjustCapacity staffs upcoming tire change times of given period, zero until includes all future times
+func (s *staffService) adjustCapacity(from time.Time, until time.Time) []*tireChangeTimeEntity {
+	if now := time.Now(); from.Before(now) {
+		from = now
+	}
+
+	mechanics := s.repository.all()
+	tireChangeTimes := s.tireChangeTimeRepository.allByTimeRange(from, until)
+
+	for _, tireChangeTime := range tireChangeTimes {
+		wasAvailable := tireChangeTime.Available
+
+		if !tireChangeTime.staff(s.staffedBays(mechanics, tireChangeTime.Time)) {
+			continue
+		}
+
+		s.tireChangeTimeRepository.save(tireChangeTime)
+
+		if !wasAvailable && tireChangeTime.Available {
+			s.waitlist.offer(tireChangeTime)
+		}
+	}
+
+	log.Infof("adjusted capacity of %d tire change times from %s", len(tireChangeTimes), from)
+
+	return tireChangeTimes
+}
+
+// staffedBays returns the amount of bays operated by mechanics on duty at given time, limited by bays of the workshop
+func (s *staffService) staffedBays(mechanics []*mechanicEntity, t time.Time) uint {
+	onDuty := uint(0)
+
+	for _, mechanic := range mechanics {
+		if mechanic.onDuty(t) {
+			onDuty++
+		}
+	}
+
+	if onDuty > s.baysPerTimeSlot {
+		return s.baysPerTimeSlot
+	}
+
+	return onDuty
+}
+
+// bookingRules validates workshop constraints applied to every new booking on top of tire change time availability
+type bookingRules struct {
+	config Config
+}
+
+func newBookingRules(config Config) *bookingRules {
+	return &bookingRules{config: config}
+}
+
+// check verifies whether contact is allowed to book given tire change time within transaction of given repository
+func (r *bookingRules) check(
+	repository *tireChangeTimeRepository,
+	tireChangeTime *tireChangeTimeEntity,
+	contactInformation string,
+) error {
+	if !tireChangeTime.Available || tireChangeTime.bookingOf(contactInformation) != nil {
+		return nil
+	}
+
+	now := time.Now()
+
+	if err := tireChangeTime.checkBookingWindow(now, r.config.MinBookingLeadTime, r.config.MaxBookingAdvance); err != nil {
+		return err
+	}
+
+	if r.config.MaxActiveBookingsPerContact > 0 &&
+		repository.countActiveByContact(contactInformation, now) >= r.config.MaxActiveBookingsPerContact {
+		return newBookingLimitExceededError(contactInformation, r.config.MaxActiveBookingsPerContact)
+	}
+
+	return nil
+}
+
+// pricingEngine quotes service prices in pennies by vehicle type and time of the service
+type pricingEngine struct {
+	vehicleTypeFactors map[string]int64
+	peakHours          map[int]bool
+	peakHourSurcharge  int64
+	peakSeasonMonths   map[time.Month]bool
+	seasonalSurcharge  int64
+	weekendSurcharge   int64
+}
+
+// newPricingEngine creates pricing engine with given workshop price list
+func newPricingEngine(pricing Pricing) *pricingEngine {
+	engine := &pricingEngine{
+		vehicleTypeFactors: pricing.VehicleTypeFactors,
+		peakHours:          map[int]bool{},
+		peakHourSurcharge:  pricing.PeakHourSurcharge,
+		peakSeasonMonths:   map[time.Month]bool{},
+		seasonalSurcharge:  pricing.SeasonalSurcharge,
+		weekendSurcharge:   pricing.WeekendSurcharge,
+	}
+
+	for _, hour := range pricing.PeakHours {
+		engine.peakHours[hour] = true
+	}
+
+	for _, month := range pricing.PeakSeasonMonths {
+		engine.peakSeasonMonths[month] = true
+	}
+
+	return engine
+}
+
+// vehicleTypes returns all vehicle types priced by the workshop in alphabetical order
+func (e *pricingEngine) vehicleTypes() []string {
+	vehicleTypes := make([]string, 0, len(e.vehicleTypeFactors))
+
+	for vehicleType := range e.vehicleTypeFactors {
+		vehicleTypes = append(vehicleTypes, vehicleType)
+	}
+
+	sort.Strings(vehicleTypes)
+
+	return vehicleTypes
+}
+
+func (e *pricingEngine) supports(vehicleType string) bool {
+	_, ok := e.vehicleTypeFactors[vehicleType]
+
+	return ok
+}
+
+// quote calculates price of the service in pennies, rounded half up
+func (e *pricingEngine) quote(serviceType *serviceTypeEntity, vehicleType string, startTime time.Time) int64 {
+	startTime = shared.InServerTimeZone(startTime)
+	surcharge := int64(0)
+
+	if e.peakHours[startTime.Hour()] {
+		surcharge += e.peakHourSurcharge
+	}
+
+	if e.peakSeasonMonths[startTime.Month()] {
+		surcharge += e.seasonalSurcharge
+	}
+
+	if startTime.Weekday() == time.Saturday || startTime.Weekday() == time.Sunday {
+		surcharge += e.weekendSurcharge
+	}
+
+	// factor and surcharge are percents, the product is scaled back from percents of percents
+	return (serviceType.PricePennies*e.vehicleTypeFactors[vehicleType]*(100+surcharge) + 5000) / 10000
 }
@@ -1,8 +1,13 @@
 package shared
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
 	"time"
 )
 
@@ -45,3 +50,79 @@ func GinRusMiddleware(logger *log.Logger, timeFormat string, utc bool) gin.Handl
 		}
 	}
 }
+
+// ConditionalGetMiddleware returns a gin.HandlerFunc (middleware) supporting conditional GET requests.
+//
+// Successful GET responses are buffered and tagged by ETag computed from the response body, Cache-Control header
+// is set to given value. Response body is omitted with 304 Not Modified status when If-None-Match header matches
+// the ETag or, when If-None-Match is not given, If-Modified-Since header is not before Last-Modified header set
+// by the handler.
+func ConditionalGetMiddleware(cacheControl string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if c.Writer.Status() != http.StatusOK {
+			_, _ = c.Writer.Write(writer.body.Bytes())
+			return
+		}
+
+		etag := fmt.Sprintf(`"%x"`, sha256.Sum256(writer.body.Bytes()))
+		c.Header("ETag", etag)
+		c.Header("Cache-Control", cacheControl)
+		c.Header("Vary", "Accept")
+
+		if isNotModified(c.Request, etag, c.Writer.Header().Get("Last-Modified")) {
+			c.Writer.WriteHeader(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+
+		_, _ = c.Writer.Write(writer.body.Bytes())
+	}
+}
+
+func isNotModified(request *http.Request, etag string, lastModified string) bool {
+	if ifNoneMatch := request.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(request.Header.Get("If-Modified-Since"))
+
+	if err != nil || lastModified == "" {
+		return false
+	}
+
+	modified, err := http.ParseTime(lastModified)
+
+	return err == nil && !modified.After(ifModifiedSince)
+}
+
+// bufferedResponseWriter holds back response body until the whole response is known
+type bufferedResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedResponseWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
@@ -0,0 +1,247 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// MIMEYAML is media type of OpenAPI document served in YAML format
+const MIMEYAML = "application/yaml"
+
+var pathParameterPattern = regexp.MustCompile(`:(\w+)`)
+
+// OpenAPIDocument is OpenAPI 3 description of the API served under the base path of the document.
+// Operations are resolved by the routes matched by gin, so the paths of the document are relative to the base path
+// and route prefixes preceding it, e.g. /branches/{branchId}, are not described by the document.
+type OpenAPIDocument struct {
+	document           *openapi3.T
+	yaml               []byte
+	json               []byte
+	basePath           string
+	defaultContentType string
+}
+
+// NewOpenAPIDocument loads OpenAPI 3 document in YAML format of given name describing operations of routes under given
+// base path, references to other documents are resolved from the same files. Request bodies without Content-Type
+// header are validated as given default content type. Invalid document panics.
+func NewOpenAPIDocument(files fs.FS, name string, basePath string, defaultContentType string) *OpenAPIDocument {
+	data, err := fs.ReadFile(files, name)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to read OpenAPI document: %w", err))
+	}
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(_ *openapi3.Loader, location *url.URL) ([]byte, error) {
+		return fs.ReadFile(files, path.Clean(location.Path))
+	}
+
+	document, err := loader.LoadFromDataWithPath(data, &url.URL{Path: name})
+
+	if err != nil {
+		panic(fmt.Errorf("failed to load OpenAPI document: %w", err))
+	}
+
+	if err = document.Validate(loader.Context); err != nil {
+		panic(fmt.Errorf("invalid OpenAPI document: %w", err))
+	}
+
+	jsonDocument, err := json.Marshal(document)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return &OpenAPIDocument{
+		document:           document,
+		yaml:               data,
+		json:               jsonDocument,
+		basePath:           basePath,
+		defaultContentType: defaultContentType,
+	}
+}
+
+// ServeYAML writes the document in YAML format as it was given
+func (d *OpenAPIDocument) ServeYAML(c *gin.Context) {
+	c.Data(http.StatusOK, MIMEYAML+"; charset=utf-8", d.yaml)
+}
+
+// ServeJSON writes the document in JSON format
+func (d *OpenAPIDocument) ServeJSON(c *gin.Context) {
+	c.Data(http.StatusOK, gin.MIMEJSON+"; charset=utf-8", d.json)
+}
+
+// RequestValidationMiddleware returns a gin.HandlerFunc (middleware) validating parameters and bodies of requests
+// to documented operations.
+//
+// Requests not conforming to the document panic with the error converted by given function, the error wraps
+// OpenAPIRequestError naming invalid parameter. Routes outside of the base path and undocumented operations are
+// left unchecked.
+func (d *OpenAPIDocument) RequestValidationMiddleware(invalidRequest func(error) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input, err := d.requestValidationInput(c)
+
+		if input != nil && err == nil {
+			if input.Route.Operation.RequestBody != nil && c.ContentType() == "" {
+				c.Request.Header.Set("Content-Type", d.defaultContentType)
+			}
+
+			if err = openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+				panic(invalidRequest(newOpenAPIRequestError(err)))
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// ResponseValidationMiddleware returns a gin.HandlerFunc (middleware) validating responses of routes under
+// the base path, meant for debug mode to reveal drift between the document and the implementation.
+//
+// Responses are buffered, responses of undocumented operations and statuses or not conforming to the document are
+// replaced by 500 Internal Server Error describing the deviation.
+func (d *OpenAPIDocument) ResponseValidationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !d.isDocumentedPath(c) {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if err := d.validateResponse(c, writer); err != nil {
+			log.Errorf("response of %s %s does not conform to OpenAPI document: %s", c.Request.Method, c.FullPath(), err)
+
+			c.Header("Content-Type", gin.MIMEPlain+"; charset=utf-8")
+			c.Writer.WriteHeader(http.StatusInternalServerError)
+			_, _ = c.Writer.WriteString(fmt.Sprintf("response does not conform to OpenAPI document: %s", err))
+
+			return
+		}
+
+		_, _ = c.Writer.Write(writer.body.Bytes())
+	}
+}
+
+func (d *OpenAPIDocument) validateResponse(c *gin.Context, writer *bufferedResponseWriter) error {
+	input, err := d.requestValidationInput(c)
+
+	if err != nil {
+		return err
+	}
+
+	return openapi3filter.ValidateResponse(c.Request.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 writer.Status(),
+		Header:                 writer.Header(),
+		Body:                   ioutil.NopCloser(bytes.NewReader(writer.body.Bytes())),
+		Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+	})
+}
+
+// isDocumentedPath tells whether request matched route under the base path of the document
+func (d *OpenAPIDocument) isDocumentedPath(c *gin.Context) bool {
+	return c.FullPath() != "" && strings.Contains(c.FullPath(), d.basePath)
+}
+
+// requestValidationInput resolves documented operation of the route matched by the request, nil input is returned
+// for routes outside of the base path and error for undocumented operations
+func (d *OpenAPIDocument) requestValidationInput(c *gin.Context) (*openapi3filter.RequestValidationInput, error) {
+	if !d.isDocumentedPath(c) {
+		return nil, nil
+	}
+
+	fullPath := c.FullPath()
+	path := pathParameterPattern.ReplaceAllString(
+		fullPath[strings.Index(fullPath, d.basePath)+len(d.basePath):],
+		"{$1}",
+	)
+	pathItem := d.document.Paths[path]
+
+	if pathItem == nil || pathItem.GetOperation(c.Request.Method) == nil {
+		return nil, fmt.Errorf("operation %s %s is not documented", c.Request.Method, path)
+	}
+
+	pathParams := make(map[string]string, len(c.Params))
+
+	for _, param := range c.Params {
+		pathParams[param.Key] = param.Value
+	}
+
+	return &openapi3filter.RequestValidationInput{
+		Request:    c.Request,
+		PathParams: pathParams,
+		Route: &routers.Route{
+			Spec:      d.document,
+			Path:      path,
+			PathItem:  pathItem,
+			Method:    c.Request.Method,
+			Operation: pathItem.GetOperation(c.Request.Method),
+		},
+		Options: &openapi3filter.Options{},
+	}, nil
+}
+
+// OpenAPIRequestError describes request parameter or body property not conforming to OpenAPI document
+type OpenAPIRequestError struct {
+	// Name of the query or path parameter, dot separated path of the body property or body for the whole body
+	Name   string
+	Reason string
+	cause  error
+}
+
+func newOpenAPIRequestError(err error) error {
+	var requestError *openapi3filter.RequestError
+
+	if !errors.As(err, &requestError) {
+		return err
+	}
+
+	result := &OpenAPIRequestError{Name: "body", Reason: requestError.Reason, cause: err}
+
+	if requestError.Parameter != nil {
+		result.Name = requestError.Parameter.Name
+	}
+
+	var schemaError *openapi3.SchemaError
+
+	if errors.As(requestError.Err, &schemaError) {
+		result.Reason = schemaError.Reason
+
+		if pointer := schemaError.JSONPointer(); requestError.Parameter == nil && len(pointer) > 0 {
+			result.Name = strings.Join(pointer, ".")
+		}
+	} else if requestError.Err != nil && result.Reason == "" {
+		result.Reason = requestError.Err.Error()
+	} else if requestError.Err != nil {
+		result.Reason += ": " + requestError.Err.Error()
+	}
+
+	return result
+}
+
+func (e *OpenAPIRequestError) Error() string {
+	return fmt.Sprintf("request parameter %s is invalid: %s", e.Name, e.Reason)
+}
+
+func (e *OpenAPIRequestError) Unwrap() error {
+	return e.cause
+}
@@ -0,0 +1,128 @@
+package shared
+
+import (
+	"encoding/xml"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	for _, contentType := range []string{gin.MIMEXML, gin.MIMEXML2, MIMEProblemXML} {
+		openapi3filter.RegisterBodyDecoder(contentType, xmlBodyDecoder)
+	}
+}
+
+// xmlElement is generic XML element, namespaces are ignored
+type xmlElement struct {
+	XMLName  xml.Name
+	Content  string        `xml:",chardata"`
+	Children []*xmlElement `xml:",any"`
+}
+
+// xmlBodyDecoder decodes XML body into JSON compatible value guided by the schema of the body, elements are
+// matched with schema properties by names given by XML objects of the schema or by property names.
+// Elements without a matching property are ignored and values not parsable by the schema type are kept as strings
+// to fail the validation.
+func xmlBodyDecoder(
+	body io.Reader,
+	_ http.Header,
+	schema *openapi3.SchemaRef,
+	_ openapi3filter.EncodingFn,
+) (interface{}, error) {
+	var root xmlElement
+
+	if err := xml.NewDecoder(body).Decode(&root); err != nil {
+		return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+	}
+
+	return root.value(schema), nil
+}
+
+func (e *xmlElement) value(schema *openapi3.SchemaRef) interface{} {
+	if schema == nil || schema.Value == nil {
+		return e.Content
+	}
+
+	switch schema.Value.Type {
+	case "object":
+		return e.object(schema.Value)
+
+	case "array":
+		return xmlArray(e.Children, schema.Value.Items)
+
+	case "integer", "number":
+		if number, err := strconv.ParseFloat(strings.TrimSpace(e.Content), 64); err == nil {
+			return number
+		}
+
+	case "boolean":
+		if boolean, err := strconv.ParseBool(strings.TrimSpace(e.Content)); err == nil {
+			return boolean
+		}
+	}
+
+	return e.Content
+}
+
+func (e *xmlElement) object(schema *openapi3.Schema) map[string]interface{} {
+	object := make(map[string]interface{})
+
+	for name, property := range schema.Properties {
+		elementName := xmlName(name, property)
+
+		if property.Value.Type == "array" && !isWrapped(property) {
+			if items := e.childrenNamed(xmlName(elementName, property.Value.Items)); len(items) > 0 {
+				object[name] = xmlArray(items, property.Value.Items)
+			}
+
+			continue
+		}
+
+		if children := e.childrenNamed(elementName); len(children) > 0 {
+			object[name] = children[0].value(property)
+		}
+	}
+
+	return object
+}
+
+func (e *xmlElement) childrenNamed(name string) []*xmlElement {
+	var children []*xmlElement
+
+	for _, child := range e.Children {
+		if child.XMLName.Local == name {
+			children = append(children, child)
+		}
+	}
+
+	return children
+}
+
+func xmlArray(elements []*xmlElement, items *openapi3.SchemaRef) []interface{} {
+	array := make([]interface{}, 0, len(elements))
+
+	for _, element := range elements {
+		array = append(array, element.value(items))
+	}
+
+	return array
+}
+
+// xmlName returns element name given by XML object of inline schema, defaulting to given name.
+// XML names of referenced schemas name root elements, so they are not used for properties and array items
+func xmlName(name string, schema *openapi3.SchemaRef) string {
+	if schema.Ref == "" && schema.Value != nil && schema.Value.XML != nil && schema.Value.XML.Name != "" {
+		return schema.Value.XML.Name
+	}
+
+	return name
+}
+
+func isWrapped(schema *openapi3.SchemaRef) bool {
+	return schema.Value.XML != nil && schema.Value.XML.Wrapped
+}
@@ -0,0 +1,202 @@
+package shared
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	uuid "github.com/satori/go.uuid"
+	"reflect"
+	"strings"
+)
+
+const (
+	// MIMEProblemJSON is media type of RFC 7807 problem details in JSON format
+	MIMEProblemJSON = "application/problem+json"
+	// MIMEProblemXML is media type of RFC 7807 problem details in XML format
+	MIMEProblemXML = "application/problem+xml"
+	// ProblemTypeURNPrefix prefixes type URIs of problems specific to tire change workshops
+	ProblemTypeURNPrefix = "urn:tire-change-workshop:problem:"
+	// TraceIDHeader is request header carrying trace ID given by the client
+	TraceIDHeader = "X-Request-Id"
+)
+
+// Problem describes failed request by RFC 7807 problem details
+type Problem struct {
+	XMLName       xml.Name        `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	Type          string          `json:"type" xml:"type"`
+	Title         string          `json:"title" xml:"title"`
+	Status        int             `json:"status" xml:"status"`
+	Detail        string          `json:"detail" xml:"detail"`
+	Instance      string          `json:"instance" xml:"instance"`
+	InvalidParams []*InvalidParam `json:"invalid-params,omitempty" xml:"invalid-params>i,omitempty"`
+	// Alternatives is extension member suggesting tire change times to book instead of unavailable one
+	Alternatives interface{} `json:"alternatives,omitempty" xml:"alternatives>alternative,omitempty"`
+	TraceID      string      `json:"traceId" xml:"traceId"`
+}
+
+// InvalidParam names request parameter failing validation with the reason of failure
+type InvalidParam struct {
+	Name   string `json:"name" xml:"name"`
+	Reason string `json:"reason" xml:"reason"`
+}
+
+// NewProblem describes error of the request by problem of given type, validation errors are listed as invalid params.
+// Trace ID is taken from X-Request-Id header or generated when the header is missing.
+func NewProblem(c *gin.Context, problemType string, title string, status int, err error) *Problem {
+	problem := &Problem{
+		Type:     problemType,
+		Title:    title,
+		Status:   status,
+		Detail:   err.Error(),
+		Instance: c.Request.URL.RequestURI(),
+		TraceID:  c.GetHeader(TraceIDHeader),
+	}
+
+	if problem.TraceID == "" {
+		problem.TraceID = uuid.NewV4().String()
+	}
+
+	var validationErrors validator.ValidationErrors
+	var requestValidationError *RequestValidationError
+	var openAPIRequestError *OpenAPIRequestError
+
+	if errors.As(err, &openAPIRequestError) {
+		problem.InvalidParams = []*InvalidParam{{Name: openAPIRequestError.Name, Reason: openAPIRequestError.Reason}}
+	} else if errors.As(err, &requestValidationError) {
+		for i, fieldError := range requestValidationError.ValidationErrors {
+			problem.InvalidParams = append(problem.InvalidParams, &InvalidParam{
+				Name:   requestValidationError.parameterNames[i],
+				Reason: validationReason(fieldError),
+			})
+		}
+	} else if errors.As(err, &validationErrors) {
+		for _, fieldError := range validationErrors {
+			problem.InvalidParams = append(problem.InvalidParams, &InvalidParam{
+				Name:   fieldError.Field(),
+				Reason: validationReason(fieldError),
+			})
+		}
+	}
+
+	return problem
+}
+
+func validationReason(fieldError validator.FieldError) string {
+	if fieldError.Param() == "" {
+		return fmt.Sprintf("failed on the '%s' rule", fieldError.Tag())
+	}
+
+	return fmt.Sprintf("failed on the '%s=%s' rule", fieldError.Tag(), fieldError.Param())
+}
+
+// ProblemFormat returns problem details media type explicitly listed by Accept header of the request,
+// empty when client has not opted in for problem details
+func ProblemFormat(c *gin.Context) string {
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.Split(accepted, ";")[0])
+
+		if mediaType == MIMEProblemJSON || mediaType == MIMEProblemXML {
+			return mediaType
+		}
+	}
+
+	return ""
+}
+
+// RenderProblem writes problem details in given problem details media type
+func RenderProblem(c *gin.Context, format string, problem *Problem) {
+	c.Header("Content-Type", format+"; charset=utf-8")
+	c.Header(TraceIDHeader, problem.TraceID)
+
+	if format == MIMEProblemXML {
+		c.XML(problem.Status, problem)
+	} else {
+		c.JSON(problem.Status, problem)
+	}
+}
+
+// RequestValidationError describes failed validation of the request, error message is kept as given by the
+// validator while invalid fields are named by their names in the request
+type RequestValidationError struct {
+	validator.ValidationErrors
+	parameterNames []string
+}
+
+func (e *RequestValidationError) Unwrap() error {
+	return e.ValidationErrors
+}
+
+// requestValidator validates requests by gin default validator, describing validation failures by
+// RequestValidationError
+type requestValidator struct {
+	binding.StructValidator
+}
+
+func (v requestValidator) ValidateStruct(obj interface{}) error {
+	err := v.StructValidator.ValidateStruct(obj)
+
+	var validationErrors validator.ValidationErrors
+
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	requestError := &RequestValidationError{ValidationErrors: validationErrors}
+
+	for _, fieldError := range validationErrors {
+		requestError.parameterNames = append(requestError.parameterNames, parameterName(reflect.TypeOf(obj), fieldError))
+	}
+
+	return requestError
+}
+
+// UseRequestParameterNames makes validation errors name invalid fields by their names in requests for problem details,
+// names are looked up from json, form, uri and xml tags in that order. Messages of validation errors are not affected
+func UseRequestParameterNames() {
+	if _, ok := binding.Validator.(requestValidator); !ok {
+		binding.Validator = requestValidator{binding.Validator}
+	}
+}
+
+// parameterName resolves field failing validation in request of given type, struct field name is returned when the
+// field cannot be resolved
+func parameterName(requestType reflect.Type, fieldError validator.FieldError) string {
+	name := fieldError.Field()
+	fieldType := requestType
+
+	for _, fieldName := range strings.Split(fieldError.StructNamespace(), ".")[1:] {
+		for fieldType.Kind() == reflect.Ptr || fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Map {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() != reflect.Struct {
+			return fieldError.Field()
+		}
+
+		field, ok := fieldType.FieldByName(strings.Split(fieldName, "[")[0])
+
+		if !ok {
+			return fieldError.Field()
+		}
+
+		name = requestParameterName(field)
+		fieldType = field.Type
+	}
+
+	return name
+}
+
+func requestParameterName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form", "uri", "xml"} {
+		name := strings.Split(field.Tag.Get(key), ",")[0]
+
+		if name != "" && name != "-" {
+			return name[strings.LastIndex(name, ">")+1:]
+		}
+	}
+
+	return field.Name
+}
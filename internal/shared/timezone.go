@@ -0,0 +1,31 @@
+package shared
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// ServerTimeZone names time zone of the server, which tire change times, shifts and dates of workshops follow.
+// IANA name is given when the time zone is set by TZ environment variable or /etc/localtime links to zoneinfo
+// database, otherwise the zone abbreviation of current time is given
+func ServerTimeZone() string {
+	if name := time.Local.String(); name != "Local" {
+		return name
+	}
+
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if i := strings.Index(target, "zoneinfo/"); i >= 0 {
+			return target[i+len("zoneinfo/"):]
+		}
+	}
+
+	name, _ := time.Now().Zone()
+
+	return name
+}
+
+// InServerTimeZone converts given time to server time zone, in which times are stored and compared
+func InServerTimeZone(t time.Time) time.Time {
+	return t.Local()
+}